@@ -5,8 +5,10 @@ import (
 	"fmt"
 	_ "image/png"
 	"log"
+	"math/rand"
 	"os"
 	"runtime/pprof"
+	"strings"
 	"time"
 
 	"seebs.net/modus/g"
@@ -38,12 +40,61 @@ var (
 
 var pause = false
 
-var km = keys.NewMap(ebiten.KeyA, ebiten.KeyD, ebiten.KeyQ, ebiten.KeyS, ebiten.KeyW, ebiten.KeyPeriod, ebiten.KeySpace, ebiten.KeyLeft, ebiten.KeyRight, ebiten.KeyUp)
+// detail and palette the current scene is using; num and defaultPalette
+// are what new scenes start with, unless a playlist entry says otherwise.
+var (
+	detail         int
+	paletteName    string
+	defaultPalette = "rainbow"
+)
+
+var pointers keys.Pointers
+
+var km = keys.NewMap(ebiten.KeyA, ebiten.KeyD, ebiten.KeyQ, ebiten.KeyR, ebiten.KeyS, ebiten.KeyW, ebiten.KeyComma, ebiten.KeyPeriod, ebiten.KeySpace, ebiten.KeyLeft, ebiten.KeyRight, ebiten.KeyUp, ebiten.KeyDown, ebiten.KeyMinus, ebiten.KeyEqual, ebiten.KeyP)
 
 var frames = 0
 var tps float64
 var tpsStarted bool
 var useSound = true
+var rec = newRecorder("modus.gif", 0)
+
+// session records input to a file, and replay plays it back in place
+// of the keyboard.
+var session *keys.Recorder
+var replay *keys.Replay
+
+// playlist, if set, switches modes automatically. It shuffles with its
+// own generator, so shuffling doesn't change what the scenes see from
+// gctx.Rand, and a replay without the playlist stays in step.
+var playlist *modes.Playlist
+var playlistRand *rand.Rand
+
+// default playlist entry duration, in seconds
+const playlistSeconds = 30
+
+// transition, if set, is blending from the previous scene to the current
+// one. Mode switches only use transitions if transitionTicks is non-zero.
+var transition *modes.Transition
+var transitionType g.TransitionType
+var transitionTicks int
+
+// default transition duration, in ticks
+const defaultTransitionTicks = 60
+
+// sceneRunner is the part of a Scene that a Transition also implements.
+type sceneRunner interface {
+	Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error)
+	Draw(screen *ebiten.Image) error
+}
+
+// running yields the current transition, if there is one, and otherwise
+// the current scene.
+func running() sceneRunner {
+	if transition != nil {
+		return transition
+	}
+	return scene
+}
 
 func update(screen *ebiten.Image) error {
 	cTPS := ebiten.CurrentTPS()
@@ -54,7 +105,29 @@ func update(screen *ebiten.Image) error {
 		tps += cTPS
 		frames++
 	}
-	km.Update()
+	if replay != nil {
+		if !km.UpdateFrom(replay) {
+			return errors.New("replay complete")
+		}
+	} else {
+		km.Update()
+	}
+	var down map[int]keys.ScreenPoint
+	if replay != nil {
+		down = replay.Pointers()
+	} else {
+		down = keys.ScreenPointers()
+	}
+	pointers.Track(gctx, down)
+	if session != nil {
+		err := session.Record(km)
+		if err == nil {
+			err = session.Pointers(down)
+		}
+		if err != nil {
+			return err
+		}
+	}
 
 	if km.Released(ebiten.KeyQ) {
 		return errors.New("quit requested")
@@ -62,26 +135,76 @@ func update(screen *ebiten.Image) error {
 	if km.Pressed(ebiten.KeySpace) {
 		pause = !pause
 	}
-	if km.Pressed(ebiten.KeyUp) {
-		err := newMode()
+	// when replaying, mode switches come from the session file
+	next := replay == nil && km.Pressed(ebiten.KeyUp)
+	if km.Released(ebiten.KeyRight) {
+		step = true
+	}
+	if km.Pressed(ebiten.KeyR) {
+		err := rec.toggle()
 		if err != nil {
 			return err
 		}
 	}
-	if km.Released(ebiten.KeyRight) {
-		step = true
+	if km.Pressed(ebiten.KeyMinus) {
+		err := resetScene(modes.StepDetail(detail, -1), paletteName)
+		if err != nil {
+			return err
+		}
+	}
+	if km.Pressed(ebiten.KeyEqual) {
+		err := resetScene(modes.StepDetail(detail, 1), paletteName)
+		if err != nil {
+			return err
+		}
+	}
+	if km.Pressed(ebiten.KeyP) {
+		err := resetScene(detail, g.StepPalette(paletteName, 1))
+		if err != nil {
+			return err
+		}
 	}
 
 	if !pause || step {
-		stepped, err := scene.Tick(voice, km)
+		stepped, err := running().Tick(voice, km, pointers)
 		if stepped {
 			step = false
 		}
 		if err != nil {
 			return err
 		}
+		if playlist != nil && replay == nil && playlist.Tick() {
+			next = true
+		}
 	}
-	err := scene.Draw(screen)
+	// mode switches all happen after the tick, so a replay can apply them
+	// at the same point in the frame
+	if next {
+		err := newMode()
+		if err != nil {
+			return err
+		}
+	}
+	if replay != nil {
+		if ms := replay.Mode(); ms.Name != "" {
+			err := switchMode(ms)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	err := running().Draw(screen)
+	if err != nil {
+		return err
+	}
+	if transition != nil && transition.Done() {
+		err = transition.Finish()
+		transition = nil
+		if err != nil {
+			return err
+		}
+	}
+	err = rec.capture(screen)
 	if err != nil {
 		return err
 	}
@@ -94,21 +217,131 @@ func update(screen *ebiten.Image) error {
 	}
 }
 
+// newMode switches to the next playlist entry, if there's a playlist,
+// and otherwise to the next mode.
 func newMode() error {
-	currentMode = (currentMode + 1) % len(allModes)
-	mode := allModes[currentMode]
+	if playlist != nil {
+		return playEntry(playlist.Next(playlistRand))
+	}
+	return setMode((currentMode + 1) % len(allModes))
+}
+
+// playEntry switches to the mode given by a playlist entry.
+func playEntry(e modes.PlaylistEntry) error {
+	d, p := num, defaultPalette
+	if e.Detail != 0 {
+		d = e.Detail
+	}
+	if e.Palette != "" {
+		p = e.Palette
+	}
+	for i, mode := range allModes {
+		if mode.Name() == e.Mode.Name() {
+			currentMode = i
+		}
+	}
+	return startMode(e.Mode, d, p)
+}
+
+// switchMode switches to a mode recorded in a session file.
+func switchMode(ms keys.ModeSwitch) error {
+	d, p := num, defaultPalette
+	if ms.Detail != 0 {
+		d = ms.Detail
+	}
+	if ms.Palette != "" {
+		p = ms.Palette
+	}
+	if g.Palettes[p] == nil {
+		return fmt.Errorf("unknown palette %q", p)
+	}
+	for i, mode := range allModes {
+		if mode.Name() == ms.Name {
+			currentMode = i
+			return startMode(mode, d, p)
+		}
+	}
+	return fmt.Errorf("unknown mode %q", ms.Name)
+}
+
+// setMode switches to the mode at the given index in allModes.
+func setMode(index int) error {
+	currentMode = index
+	return startMode(allModes[currentMode], num, defaultPalette)
+}
+
+// startMode starts a new scene for mode, recording the switch if input
+// is being recorded. If transitions are enabled, the previous scene
+// keeps running while the transition blends into the new one.
+func startMode(mode modes.Mode, d int, p string) error {
 	fmt.Printf("new mode: %s\n", mode.Name())
+	if session != nil {
+		err := session.Mode(keys.ModeSwitch{Name: mode.Name(), Detail: d, Palette: p})
+		if err != nil {
+			return err
+		}
+	}
+	// cut short any transition that's still running
+	if transition != nil {
+		err := transition.Finish()
+		transition = nil
+		if err != nil {
+			return err
+		}
+	}
+	next, err := mode.New(gctx, d, g.Palettes[p])
+	if err != nil {
+		return err
+	}
+	detail, paletteName = d, p
 	if scene != nil {
-		scene.Hide()
-		scene = nil
+		if transitionTicks > 0 {
+			transition, err = modes.NewTransition(gctx, transitionType, scene, next, transitionTicks)
+			if err != nil {
+				return err
+			}
+		} else {
+			scene.Hide()
+		}
+	}
+	scene = next
+	return nil
+}
+
+// resetScene resets the current scene with a new detail level and
+// palette, cutting short any transition into it.
+func resetScene(d int, p string) error {
+	if d == detail && p == paletteName {
+		return nil
+	}
+	if transition != nil {
+		err := transition.Finish()
+		transition = nil
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("detail %d, palette %s\n", d, p)
+	detail, paletteName = d, p
+	return scene.Reset(d, g.Palettes[p])
+}
+
+// listModes prints the available modes, and any settings they have.
+func listModes() {
+	for _, mode := range allModes {
+		fmt.Printf("%s: %s\n", mode.Name(), mode.Description())
+		cm, ok := mode.(modes.Configurable)
+		if !ok {
+			continue
+		}
+		for _, s := range cm.Settings() {
+			fmt.Printf("  %s (%s, %g-%g, default %g): %s\n", s.Name, s.Type, s.Min, s.Max, s.Default, s.Description)
+		}
 	}
-	var err error
-	scene, err = mode.New(gctx, num, g.Palettes["rainbow"])
-	return err
 }
 
 func main() {
-	opts, _, err := gogetopt.GetOpt(os.Args[1:], "amM:n#pPqs#x#y#")
+	opts, _, err := gogetopt.GetOpt(os.Args[1:], "ac:C:d#e#f#g:G:k:K:lL:mM:n#o:pPqr:s#S#t:T#x#y#z")
 	if err != nil {
 		log.Fatalf("option parsing failed: %s\n", err)
 	}
@@ -145,9 +378,32 @@ func main() {
 			}
 		}()
 	}
+	if opts.Seen("e") || opts.Seen("r") {
+		path, every := rec.path, 0
+		if opts.Seen("e") {
+			every = opts["e"].Int
+		}
+		if opts.Seen("r") {
+			path = opts["r"].Value
+		}
+		rec = newRecorder(path, every)
+	}
 	if opts.Seen("s") {
 		timedOut = time.After(time.Duration(opts["s"].Int) * time.Second)
 	}
+	if opts.Seen("t") || opts.Seen("T") {
+		transitionTicks = defaultTransitionTicks
+		if opts.Seen("T") {
+			transitionTicks = opts["T"].Int
+		}
+		if opts.Seen("t") {
+			transitionType, err = g.ParseTransitionType(opts["t"].Value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
 	if opts.Seen("x") != opts.Seen("y") {
 		fmt.Fprintf(os.Stderr, "x and y must be used together\n")
 	}
@@ -156,18 +412,156 @@ func main() {
 		screenHeight = opts["y"].Int
 	}
 	gctx = g.NewContext(screenWidth, screenHeight, opts.Seen("a"))
+	// always use a known seed, so a run can be reproduced with -S
+	seed := time.Now().UnixNano()
+	if opts.Seen("S") {
+		seed = int64(opts["S"].Int)
+	}
+	if opts.Seen("K") {
+		f, err := os.Open(opts["K"].Value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "can't open session file: %v\n", err)
+			os.Exit(1)
+		}
+		replay, err = keys.ReadReplay(f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "can't read session file: %v\n", err)
+			os.Exit(1)
+		}
+		seed = replay.Seed
+	}
+	fmt.Printf("seed: %d\n", seed)
+	gctx.Seed(seed)
 	if opts.Seen("M") {
 		modes.ApplyList(opts["M"].Value)
 	} else {
 		modes.ApplyList(os.Getenv("MODUS_MODES"))
 	}
+	// load palettes before anything refers to them by name; a missing
+	// palettes directory is only an error if it was asked for.
+	paletteDir := "palettes"
+	if opts.Seen("G") {
+		paletteDir = opts["G"].Value
+	}
+	names, err := g.LoadPaletteDir(paletteDir)
+	if err != nil && (opts.Seen("G") || !os.IsNotExist(err)) {
+		fmt.Fprintf(os.Stderr, "can't load palettes: %v\n", err)
+		os.Exit(1)
+	}
+	if len(names) > 0 {
+		fmt.Printf("loaded palettes: %s\n", strings.Join(names, ", "))
+	}
+	if opts.Seen("g") {
+		name := opts["g"].Value
+		if g.Palettes[name] == nil {
+			name, err = g.LoadPalette(name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "can't load palette: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		defaultPalette = name
+	}
+	if opts.Seen("c") || opts.Seen("C") {
+		config := make(modes.Config)
+		if opts.Seen("c") {
+			f, err := os.Open(opts["c"].Value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "can't open config file: %v\n", err)
+				os.Exit(1)
+			}
+			config, err = modes.LoadConfig(f)
+			f.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "can't read config file: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if opts.Seen("C") {
+			err := config.ParseOverrides(opts["C"].Value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "invalid setting: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		err := modes.Configure(config)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid config: %v\n", err)
+			os.Exit(1)
+		}
+	}
 	allModes = modes.ListModes()
+	if opts.Seen("l") {
+		listModes()
+		return
+	}
+	if opts.Seen("o") {
+		frames := 1
+		if opts.Seen("f") {
+			frames = opts["f"].Int
+		}
+		r, err := newRenderer(opts["o"].Value, frames, seed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "renderer error: %v\n", err)
+			os.Exit(1)
+		}
+		err = ebiten.Run(r.update, screenWidth, screenHeight, 1, "Miracle Modus")
+		if err != nil && err != errRenderDone {
+			fmt.Fprintf(os.Stderr, "render error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if opts.Seen("L") || opts.Seen("d") {
+		seconds := playlistSeconds
+		if opts.Seen("d") {
+			seconds = opts["d"].Int
+		}
+		if seconds < 1 {
+			fmt.Fprintf(os.Stderr, "playlist duration must be at least one second\n")
+			os.Exit(1)
+		}
+		ticks := seconds * modes.TicksPerSecond
+		if opts.Seen("L") {
+			f, err := os.Open(opts["L"].Value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "can't open playlist: %v\n", err)
+				os.Exit(1)
+			}
+			playlist, err = modes.ReadPlaylist(f, allModes, ticks)
+			f.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "can't read playlist: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			playlist = modes.NewPlaylist(allModes, ticks)
+		}
+		if opts.Seen("z") {
+			playlist.Shuffle = true
+		}
+		playlistRand = rand.New(rand.NewSource(seed))
+	}
 	currentMode = -1
-	err = newMode()
+	if replay != nil {
+		err = switchMode(replay.Start)
+	} else {
+		err = newMode()
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "scene error: %v\n", err)
 		os.Exit(1)
 	}
+	if opts.Seen("k") {
+		f, err := os.Create(opts["k"].Value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "can't create session file: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		session = keys.NewRecorder(f, seed, keys.ModeSwitch{Name: allModes[currentMode].Name(), Detail: detail, Palette: paletteName})
+	}
 	if useSound {
 		voice, err = sound.NewVoice("breath", 8)
 		if err != nil {
@@ -175,8 +569,23 @@ func main() {
 			os.Exit(1)
 		}
 	}
+	if opts.Seen("r") {
+		err = rec.start()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "recorder error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 	if err = ebiten.Run(update, screenWidth, screenHeight, 1, "Miracle Modus"); err != nil {
 		fmt.Fprintf(os.Stderr, "frames: %d, TPS %.2f\n", frames, tps/float64(frames))
 		fmt.Fprintf(os.Stderr, "exiting: %s\n", err)
 	}
+	if err = rec.stop(); err != nil {
+		fmt.Fprintf(os.Stderr, "recorder error: %v\n", err)
+	}
+	if session != nil {
+		if err = session.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "session error: %v\n", err)
+		}
+	}
 }
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// recorder captures every Nth drawn frame, either as a numbered sequence
+// of PNG files in a directory, or as an animated GIF. A path ending in
+// .gif selects GIF output; anything else is treated as a directory.
+//
+// GIF frames are held in memory until recording stops, so they're
+// shrunk, and by default fewer of them are kept.
+type recorder struct {
+	path      string
+	every     int // capture one frame out of this many
+	gif       bool
+	recording bool
+	drawn     int // frames drawn since recording started
+	captured  int // frames captured since recording started
+	frame     int // PNG frames written, across all recordings
+	session   int // recordings started, used to name GIF files
+	anim      *gif.GIF
+}
+
+// fps is the frame rate ebiten tries to draw at, used to compute GIF
+// frame delays.
+const fps = 60
+
+const (
+	// default frames per capture for GIFs, giving 20 frames a second
+	gifEvery = 3
+	// GIF frames are shrunk by this factor in each dimension
+	gifShrink = 2
+)
+
+// newRecorder creates a recorder capturing one frame out of every, or,
+// if every is 0, every frame for PNGs and every gifEvery frames for GIFs.
+func newRecorder(path string, every int) *recorder {
+	isGIF := strings.HasSuffix(strings.ToLower(path), ".gif")
+	if every < 1 {
+		every = 1
+		if isGIF {
+			every = gifEvery
+		}
+	}
+	return &recorder{path: path, every: every, gif: isGIF}
+}
+
+// toggle starts or stops recording.
+func (r *recorder) toggle() error {
+	if r.recording {
+		return r.stop()
+	}
+	return r.start()
+}
+
+func (r *recorder) start() error {
+	if r.recording {
+		return nil
+	}
+	if !r.gif {
+		err := os.MkdirAll(r.path, 0755)
+		if err != nil {
+			return err
+		}
+	}
+	r.session++
+	r.drawn = 0
+	r.captured = 0
+	r.anim = nil
+	if r.gif {
+		r.anim = &gif.GIF{}
+	}
+	r.recording = true
+	fmt.Printf("recording to %s\n", r.name())
+	return nil
+}
+
+// stop stops recording, writing out the animation for GIF recordings.
+func (r *recorder) stop() error {
+	if !r.recording {
+		return nil
+	}
+	r.recording = false
+	if !r.gif {
+		fmt.Printf("recorded %d frames\n", r.captured)
+		return nil
+	}
+	anim := r.anim
+	r.anim = nil
+	if len(anim.Image) == 0 {
+		return nil
+	}
+	f, err := os.Create(r.name())
+	if err != nil {
+		return err
+	}
+	err = gif.EncodeAll(f, anim)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	fmt.Printf("recorded %d frames to %s\n", r.captured, r.name())
+	return f.Close()
+}
+
+// name yields the file or directory the current recording goes to. GIF
+// recordings after the first get a number added, so they don't overwrite
+// each other.
+func (r *recorder) name() string {
+	if !r.gif || r.session < 2 {
+		return r.path
+	}
+	ext := filepath.Ext(r.path)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(r.path, ext), r.session, ext)
+}
+
+// capture records the drawn frame, if recording and it's one of the
+// frames to keep.
+func (r *recorder) capture(img image.Image) error {
+	if !r.recording {
+		return nil
+	}
+	r.drawn++
+	if (r.drawn-1)%r.every != 0 {
+		return nil
+	}
+	r.captured++
+	if !r.gif {
+		err := writePNG(filepath.Join(r.path, fmt.Sprintf("frame-%05d.png", r.frame)), img)
+		r.frame++
+		return err
+	}
+	small := shrink(img, gifShrink)
+	bounds := small.Bounds()
+	p := image.NewPaletted(bounds, palette.Plan9)
+	draw.FloydSteinberg.Draw(p, bounds, small, bounds.Min)
+	r.anim.Image = append(r.anim.Image, p)
+	// GIF delays are in hundredths of a second
+	r.anim.Delay = append(r.anim.Delay, (r.every*100+fps/2)/fps)
+	return nil
+}
+
+// shrink scales img down by n in each dimension, averaging each n by n
+// block of pixels.
+func shrink(img image.Image, n int) *image.RGBA {
+	src := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, src.Dx()/n, src.Dy()/n))
+	area := uint32(n * n)
+	for y := 0; y < dst.Rect.Dy(); y++ {
+		for x := 0; x < dst.Rect.Dx(); x++ {
+			var r, g, b, a uint32
+			for dy := 0; dy < n; dy++ {
+				for dx := 0; dx < n; dx++ {
+					pr, pg, pb, pa := img.At(src.Min.X+x*n+dx, src.Min.Y+y*n+dy).RGBA()
+					r, g, b, a = r+pr, g+pg, b+pb, a+pa
+				}
+			}
+			// RGBA yields 16-bit values
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / area >> 8), uint8(g / area >> 8), uint8(b / area >> 8), uint8(a / area >> 8)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"seebs.net/modus/g"
+	"seebs.net/modus/modes"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// renderer runs every mode in turn, drawing each scene into an offscreen
+// image rather than the screen, and writing the frames out as PNG files.
+// ebiten can only read back pixels once its loop is running, so the
+// renderer still runs from the update function; the screen just shows
+// a copy of the offscreen image.
+type renderer struct {
+	dir       string
+	frames    int // frames to write for each mode
+	seed      int64
+	img       *ebiten.Image
+	scene     modes.Scene
+	modeIndex int
+	frame     int
+}
+
+var errRenderDone = errors.New("rendering complete")
+
+func newRenderer(dir string, frames int, seed int64) (*renderer, error) {
+	if frames < 1 {
+		frames = 1
+	}
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	img, err := ebiten.NewImage(screenWidth, screenHeight, ebiten.FilterDefault)
+	if err != nil {
+		return nil, err
+	}
+	return &renderer{dir: dir, frames: frames, seed: seed, img: img, modeIndex: -1}, nil
+}
+
+// nextScene hides the current scene, if any, and creates a scene for the
+// next mode, returning errRenderDone when there are no more modes. The
+// context is reseeded for each mode, so a mode's frames don't depend on
+// which modes were rendered before it.
+func (r *renderer) nextScene() error {
+	if r.scene != nil {
+		r.scene.Hide()
+		r.scene = nil
+	}
+	r.modeIndex++
+	r.frame = 0
+	if r.modeIndex >= len(allModes) {
+		return errRenderDone
+	}
+	mode := allModes[r.modeIndex]
+	fmt.Printf("rendering mode: %s\n", mode.Name())
+	gctx.Seed(r.seed)
+	var err error
+	r.scene, err = mode.New(gctx, num, g.Palettes[defaultPalette])
+	if err != nil {
+		return fmt.Errorf("mode %s: %v", mode.Name(), err)
+	}
+	return nil
+}
+
+// update ticks the current scene until it changes, then draws it and
+// writes out the frame. Scenes get no voice and no keys, so the output
+// depends only on the mode.
+func (r *renderer) update(screen *ebiten.Image) error {
+	if r.scene == nil || r.frame >= r.frames {
+		err := r.nextScene()
+		if err != nil {
+			return err
+		}
+	}
+	stepped, err := r.scene.Tick(nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	if !stepped {
+		return nil
+	}
+	r.img.Fill(color.Black)
+	err = r.scene.Draw(r.img)
+	if err != nil {
+		return err
+	}
+	err = r.write(fmt.Sprintf("%s-%04d.png", allModes[r.modeIndex].Name(), r.frame))
+	if err != nil {
+		return err
+	}
+	r.frame++
+	return screen.DrawImage(r.img, nil)
+}
+
+// write saves the offscreen image to the named file in the output
+// directory.
+func (r *renderer) write(name string) error {
+	return writePNG(filepath.Join(r.dir, name), r.img)
+}
+
+// writePNG saves img to the given path as a PNG file.
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = png.Encode(f, img)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -22,11 +22,24 @@ type FloatingCell interface {
 	Z() *float32
 }
 
+// BlendMode selects how a floating cell combines with whatever is
+// drawn under it.
+type BlendMode int
+
+const (
+	// BlendAdd cells add their color to what's under them, as grid
+	// cells do. This is the default.
+	BlendAdd BlendMode = iota
+	// BlendNormal cells are drawn over what's under them.
+	BlendNormal
+)
+
 // FloatingCell represents a single floating cell, which may be rendered
 // in a non-integer location over a grid.
 type FloatingCellBase struct {
 	Cell
-	loc FLoc
+	BlendMode BlendMode
+	loc       FLoc
 }
 
 func (f *FloatingCellBase) C() *Cell {
@@ -2,17 +2,20 @@ package g
 
 import (
 	"image/color"
+	"math/rand"
+	"time"
 
 	"github.com/hajimehoshi/ebiten"
 )
 
 // Context represents a graphics context, basically providing a cache of
-// screen size for now.
+// screen size and a random number source for now.
 type Context struct {
 	w, h        int
 	fsaa        *ebiten.Image
 	fsaaOp      *ebiten.DrawImageOptions
 	multisample bool
+	rng         *rand.Rand
 }
 
 // RenderType represents the way a thing is drawn; for instance, which
@@ -21,9 +24,10 @@ type RenderType int
 
 // NewContext creates a new context, corresponding to a window with
 // the specified width and height. If multisample is set, it scales
-// everything by 2x internally.
+// everything by 2x internally. The context's random number source is
+// seeded from the current time; use Seed to make a run reproducible.
 func NewContext(w, h int, multisample bool) *Context {
-	ctx := &Context{w: w, h: h, multisample: multisample}
+	ctx := &Context{w: w, h: h, multisample: multisample, rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
 	if multisample {
 		ctx.fsaa, _ = ebiten.NewImage(w*2, h*2, ebiten.FilterLinear)
 		ctx.fsaaOp = &ebiten.DrawImageOptions{}
@@ -32,10 +36,22 @@ func NewContext(w, h int, multisample bool) *Context {
 	return ctx
 }
 
+// Seed reseeds the context's random number source. Everything created
+// from the context shares that source, so a scene created after seeding
+// the context with a given value behaves the same way every time.
+func (c *Context) Seed(seed int64) {
+	c.rng.Seed(seed)
+}
+
+// Rand yields the context's random number source.
+func (c *Context) Rand() *rand.Rand {
+	return c.rng
+}
+
 // NewSquareGrid returns a grid of squares with width "w"
 // across its wider dimension.
 func (c *Context) NewSquareGrid(w int, r RenderType, p *Palette) *SquareGrid {
-	return newSquareGrid(w, r, p, c.w, c.h)
+	return newSquareGrid(w, r, p, c.w, c.h, c.rng)
 }
 
 // NewParticles returns a particle-emitter source.
@@ -47,7 +63,7 @@ func (c *Context) NewParticleSystem(w int, r RenderType, p *Palette, particles P
 // NewHexGrid returns a grid of hexes with width "w"
 // across its wider dimension.
 func (c *Context) NewHexGrid(w int, r RenderType, p *Palette) *HexGrid {
-	return newHexGrid(w, r, p, c.w, c.h)
+	return newHexGrid(w, r, p, c.w, c.h, c.rng)
 }
 
 // NewDotGrid returns a grid of dots with width "w" across its wider
@@ -60,7 +76,7 @@ func (c *Context) NewDotGrid(w int, thickness float32, depth int, r RenderType,
 // NewSpiral returns a spiral for the given Context.
 func (c *Context) NewSpiral(depth int, r RenderType, points int, p *Palette, cycles int, offset int) *Spiral {
 	scale, ox, oy, _, _ := c.Centered()
-	return newSpiral(depth, r, points, p, cycles, offset, scale, ox, oy)
+	return newSpiral(depth, r, points, p, cycles, offset, scale, ox, oy, c.rng)
 }
 
 func (c *Context) NewPolyline(thickness int, r RenderType, p *Palette) *PolyLine {
@@ -91,20 +107,39 @@ func (c *Context) NewText(fontName string, size int, p *Palette) (*Text, error)
 // Meanwhile, coordX will be 0.5; the lowest X coordinate should be -1.5, and
 // the highest 1.5.
 func (c *Context) Centered() (scale, offsetX, offsetY, coordX, coordY float32) {
-	if c.w > c.h {
-		scale = float32(c.h) / 2
+	return centered(c.w, c.h)
+}
+
+// centered computes the values Centered yields for a w x h screen.
+func centered(w, h int) (scale, offsetX, offsetY, coordX, coordY float32) {
+	if w > h {
+		scale = float32(h) / 2
 		offsetY = scale
-		offsetX = float32(c.w) / 2
-		coordX = (float32(c.w) - float32(c.h)) / float32(c.h)
+		offsetX = float32(w) / 2
+		coordX = (float32(w) - float32(h)) / float32(h)
 	} else {
-		scale = float32(c.w) / 2
+		scale = float32(w) / 2
 		offsetX = scale
-		offsetY = float32(c.h) / 2
-		coordY = (float32(c.h) - float32(c.w)) / float32(c.w)
+		offsetY = float32(h) / 2
+		coordY = (float32(h) - float32(w)) / float32(w)
 	}
 	return scale, offsetX, offsetY, coordX, coordY
 }
 
+// toScreen converts a point in Centered coordinates for a w x h screen
+// to screen coordinates.
+func toScreen(p Point, w, h int) (x, y float32) {
+	scale, ox, oy, _, _ := centered(w, h)
+	return p.X*scale + ox, p.Y*scale + oy
+}
+
+// FromScreen converts screen coordinates, such as a cursor position, to
+// the coordinate space described by Centered.
+func (c *Context) FromScreen(x, y int) Point {
+	scale, ox, oy, _, _ := c.Centered()
+	return Point{X: (float32(x) - ox) / scale, Y: (float32(y) - oy) / scale}
+}
+
 func (c *Context) DrawSize() (int, int) {
 	if c.multisample {
 		return c.w * 2, c.h * 2
@@ -0,0 +1,29 @@
+package g_test
+
+import (
+	"testing"
+
+	"seebs.net/modus/g"
+)
+
+func TestContextSeed(t *testing.T) {
+	var locs [2][]g.ILoc
+	var points [2]g.MovingPoint
+	for i := range locs {
+		c := g.NewContext(1280, 960, false)
+		c.Seed(23)
+		gr := c.NewSquareGrid(20, 1, g.Palettes["rainbow"])
+		for j := 0; j < 10; j++ {
+			locs[i] = append(locs[i], gr.NewLoc())
+			points[i].PerturbVelocity(c.Rand())
+		}
+	}
+	for j := range locs[0] {
+		if locs[0][j] != locs[1][j] {
+			t.Errorf("location %d: expected matching locations, got %v and %v", j, locs[0][j], locs[1][j])
+		}
+	}
+	if points[0].Velocity != points[1].Velocity {
+		t.Errorf("expected matching velocities, got %v and %v", points[0].Velocity, points[1].Velocity)
+	}
+}
@@ -0,0 +1,85 @@
+package g
+
+// Curve evaluation, for drawing smooth curves as PolyLines.
+
+// CubicBezier yields the point t of the way along the cubic Bezier curve
+// from p0 to p3, with control points p1 and p2.
+func CubicBezier(p0, p1, p2, p3 Point, t float32) Point {
+	u := 1 - t
+	a, b, c, d := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+	return Point{
+		X: a*p0.X + b*p1.X + c*p2.X + d*p3.X,
+		Y: a*p0.Y + b*p1.Y + c*p2.Y + d*p3.Y,
+	}
+}
+
+// CatmullRom yields the point t of the way from p1 to p2 along a uniform
+// Catmull-Rom spline, which passes through all four points.
+func CatmullRom(p0, p1, p2, p3 Point, t float32) Point {
+	t2, t3 := t*t, t*t*t
+	a, b, c, d := -t3+2*t2-t, 3*t3-5*t2+2, -3*t3+4*t2+t, t3-t2
+	return Point{
+		X: (a*p0.X + b*p1.X + c*p2.X + d*p3.X) / 2,
+		Y: (a*p0.Y + b*p1.Y + c*p2.Y + d*p3.Y) / 2,
+	}
+}
+
+// setLength makes pl have exactly n points, keeping the paint of any
+// existing points.
+func (pl *PolyLine) setLength(n int) {
+	if cap(pl.Points) < n {
+		pts := make([]LinePoint, n)
+		copy(pts, pl.Points)
+		pl.Points = pts
+	}
+	pl.Points = pl.Points[:n]
+}
+
+// Bezier sets pl's points to a cubic Bezier curve from p0 to p3, with
+// control points p1 and p2, tessellated into the given number of
+// segments. Existing points keep their paint.
+func (pl *PolyLine) Bezier(p0, p1, p2, p3 Point, segments int) {
+	if segments < 1 {
+		segments = 1
+	}
+	pl.setLength(segments + 1)
+	for i := range pl.Points {
+		pt := CubicBezier(p0, p1, p2, p3, float32(i)/float32(segments))
+		pl.Points[i].X, pl.Points[i].Y = pt.X, pt.Y
+	}
+	pl.Dirty()
+}
+
+// CatmullRom sets pl's points to a Catmull-Rom spline passing through
+// each of pts, with the given number of segments between each pair of
+// points. Existing points keep their paint.
+func (pl *PolyLine) CatmullRom(pts []Point, segments int) {
+	if len(pts) < 2 {
+		pl.Reset()
+		return
+	}
+	if segments < 1 {
+		segments = 1
+	}
+	spans := len(pts) - 1
+	pl.setLength(spans*segments + 1)
+	for span := 0; span < spans; span++ {
+		// the ends are treated as though the first and last points
+		// were doubled.
+		p0, p1, p2, p3 := pts[span], pts[span], pts[span+1], pts[span+1]
+		if span > 0 {
+			p0 = pts[span-1]
+		}
+		if span < spans-1 {
+			p3 = pts[span+2]
+		}
+		for i := 0; i < segments; i++ {
+			pt := CatmullRom(p0, p1, p2, p3, float32(i)/float32(segments))
+			lp := &pl.Points[span*segments+i]
+			lp.X, lp.Y = pt.X, pt.Y
+		}
+	}
+	last := &pl.Points[spans*segments]
+	last.X, last.Y = pts[spans].X, pts[spans].Y
+	pl.Dirty()
+}
@@ -0,0 +1,37 @@
+package g_test
+
+import (
+	"testing"
+
+	"seebs.net/modus/g"
+)
+
+func TestCurves(t *testing.T) {
+	c := g.NewContext(1280, 960, false)
+	pl := c.NewPolyline(1, 1, g.Palettes["rainbow"])
+	pts := []g.Point{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 0}, {X: 3, Y: 1}}
+	pl.Bezier(pts[0], pts[1], pts[2], pts[3], 8)
+	if pl.Length() != 9 {
+		t.Fatalf("bezier: expected 9 points, got %d", pl.Length())
+	}
+	if first, last := pl.Point(0), pl.Point(8); first.X != 0 || first.Y != 0 || last.X != 3 || last.Y != 1 {
+		t.Errorf("bezier: expected endpoints 0,0 and 3,1, got %g,%g and %g,%g", first.X, first.Y, last.X, last.Y)
+	}
+	if mid := pl.Point(4); mid.X != 1.5 || mid.Y != 0.5 {
+		t.Errorf("bezier: expected midpoint 1.5,0.5, got %g,%g", mid.X, mid.Y)
+	}
+	pl.Point(0).P = 3
+	pl.CatmullRom(pts, 4)
+	if pl.Length() != 13 {
+		t.Fatalf("catmull-rom: expected 13 points, got %d", pl.Length())
+	}
+	for i, pt := range pts {
+		got := pl.Point(i * 4)
+		if got.X != pt.X || got.Y != pt.Y {
+			t.Errorf("catmull-rom: expected point %d to be %g,%g, got %g,%g", i*4, pt.X, pt.Y, got.X, got.Y)
+		}
+	}
+	if pl.Point(0).P != 3 {
+		t.Errorf("catmull-rom: expected existing paint to be kept")
+	}
+}
@@ -94,15 +94,15 @@ func (m *MovingPoint) SetBounds(min, max Point) {
 }
 
 // PerturbVelocity randomly increments or decrements the velocity
-// components.
-func (m *MovingPoint) PerturbVelocity() {
-	switch rand.Intn(3) {
+// components, using the given random number source.
+func (m *MovingPoint) PerturbVelocity(rng *rand.Rand) {
+	switch rng.Intn(3) {
 	case 0:
 		m.Velocity.X += 0.001
 	case 1:
 		m.Velocity.X -= 0.001
 	}
-	switch rand.Intn(3) {
+	switch rng.Intn(3) {
 	case 0:
 		m.Velocity.Y += 0.001
 	case 1:
@@ -17,20 +17,30 @@ type SquareGrid struct {
 	vertices      []ebiten.Vertex
 	base          []ebiten.Vertex
 	indices       []uint16
+	// scratch space for drawing extra cells with different blend modes
+	addIndices, normalIndices []uint16
 	// not really a depth anymore; selects which of several textures to use
 	render RenderType
 	ox, oy int
+	sx, sy int     // screen size
 	scale  float32 // actual size in pixels. integer plz.
+	rng    *rand.Rand
+}
+
+// Rand yields the grid's random number source, which it shares with
+// the context that created it.
+func (gr *SquareGrid) Rand() *rand.Rand {
+	return gr.rng
 }
 
 // RandRow yields a random valid row.
 func (gr *SquareGrid) RandRow() int {
-	return int(rand.Int31n(int32(gr.Height)))
+	return int(gr.rng.Int31n(int32(gr.Height)))
 }
 
 // RandCol yields a random valid column.
 func (gr *SquareGrid) RandCol() int {
-	return int(rand.Int31n(int32(gr.Width)))
+	return int(gr.rng.Int31n(int32(gr.Width)))
 }
 
 // NewLoc yields a random valid location.
@@ -61,7 +71,7 @@ func (gr *SquareGrid) Palette() *Palette {
 	return gr.palette
 }
 
-func newSquareGrid(w int, r RenderType, p *Palette, sx, sy int) *SquareGrid {
+func newSquareGrid(w int, r RenderType, p *Palette, sx, sy int, rng *rand.Rand) *SquareGrid {
 	var h int
 	var scale float32
 	if sx > sy {
@@ -83,7 +93,10 @@ func newSquareGrid(w int, r RenderType, p *Palette, sx, sy int) *SquareGrid {
 		scale:   scale,
 		ox:      (sx - int(scale)*w) / 2,
 		oy:      (sy - int(scale)*h) / 2,
+		sx:      sx,
+		sy:      sy,
 		base:    squareData.vsByR[r],
+		rng:     rng,
 	}
 	gr.Cells = make([][]Cell, gr.Width)
 	for idx := range gr.Cells {
@@ -129,6 +142,14 @@ type Grid interface {
 	Splash(ILoc, int, int, GridFunc)
 	Iterate(GridFunc)
 	NewExtraCell() FloatingCell
+	// Pick yields the location of the cell at the given screen
+	// coordinates, such as a cursor position, and the cell there, which
+	// is nil if the coordinates are outside the grid. Screen coordinates
+	// are the same whether or not the context is multisampled.
+	Pick(x, y int) (ILoc, *Cell)
+	// PickCentered is Pick for a point in the coordinates described by
+	// Context.Centered.
+	PickCentered(p Point) (ILoc, *Cell)
 }
 
 // GridFunc is a general callback for operations on the grid.
@@ -209,7 +230,7 @@ func (gr *SquareGrid) Neighbors(l ILoc, fn GridFunc) {
 	gr.Splash(l, 1, 1, fn)
 }
 
-func (gr *SquareGrid) drawCell(vs []ebiten.Vertex, c *Cell, l FLoc, xscale, yscale float32) {
+func (gr *SquareGrid) drawCell(vs []ebiten.Vertex, c *Cell, l FLoc, xscale, yscale, offsetX, offsetY float32) {
 	vs = vs[0:4]
 	// xscale and yscale are actually half the size of a default square.
 	// thus, dx/dy are the offsets (whether positive or negative) of
@@ -218,7 +239,7 @@ func (gr *SquareGrid) drawCell(vs []ebiten.Vertex, c *Cell, l FLoc, xscale, ysca
 	dx, dy := xscale*c.Scale, yscale*c.Scale
 	// we want to be a half-square offset, and we have a half-square size,
 	// so X*2+1 => the center of square X.
-	ox, oy := xscale*((l.X*2)+1), yscale*((l.Y*2)+1)
+	ox, oy := xscale*((l.X*2)+1)+offsetX, yscale*((l.Y*2)+1)+offsetY
 	if c.Theta != 0 {
 		a := IdentityAffine()
 		a.Rotate(c.Theta)
@@ -243,26 +264,79 @@ func (gr *SquareGrid) drawCell(vs []ebiten.Vertex, c *Cell, l FLoc, xscale, ysca
 	vs[3].ColorR, vs[3].ColorG, vs[3].ColorB, vs[3].ColorA = r, g, b, c.Alpha
 }
 
+// CellAt yields the location of the square at the given screen
+// coordinates, and the cell there, which is nil if the coordinates are
+// outside the grid.
+func (gr *SquareGrid) CellAt(x, y int) (l ILoc, c *Cell) {
+	return gr.cellAt(float32(x), float32(y))
+}
+
+func (gr *SquareGrid) cellAt(x, y float32) (l ILoc, c *Cell) {
+	x, y = x-float32(gr.ox), y-float32(gr.oy)
+	l = ILoc{X: int(math.Floor(x / gr.scale)), Y: int(math.Floor(y / gr.scale))}
+	if l.X < 0 || l.X >= gr.Width || l.Y < 0 || l.Y >= gr.Height {
+		return l, nil
+	}
+	return l, &gr.Cells[l.X][l.Y]
+}
+
+// Pick yields the location and cell at the given screen coordinates.
+func (gr *SquareGrid) Pick(x, y int) (ILoc, *Cell) {
+	return gr.CellAt(x, y)
+}
+
+// PickCentered yields the location and cell at the given point.
+func (gr *SquareGrid) PickCentered(p Point) (ILoc, *Cell) {
+	return gr.cellAt(toScreen(p, gr.sx, gr.sy))
+}
+
 // Draw displays the grid on the target screen.
 func (gr *SquareGrid) Draw(target *ebiten.Image, scale float32) {
 	xscale := gr.scale * scale / 2
 	yscale := gr.scale * scale / 2
+	// center the grid on the screen
+	offsetX, offsetY := float32(gr.ox)*scale, float32(gr.oy)*scale
 	op := &ebiten.DrawTrianglesOptions{CompositeMode: ebiten.CompositeModeLighter}
 	var offset int
 	gr.Iterate(func(generic Grid, l ILoc, n int, c *Cell) {
 		gr := generic.(*SquareGrid)
 		offset = ((l.Y * gr.Width) + l.X) * 4
-		gr.drawCell(gr.vertices[offset:offset+4], c, l.FLoc(), xscale, yscale)
+		gr.drawCell(gr.vertices[offset:offset+4], c, l.FLoc(), xscale, yscale, offsetX, offsetY)
 	})
 	offset = gr.Width * gr.Height * 4
 	// draw extra cells
+	normal := 0
 	for _, c := range gr.ExtraCells {
 		vs := gr.vertices[offset : offset+4]
 		copy(vs, squareData.vsByR[c.Cell.R])
-		gr.drawCell(vs, &c.Cell, *c.Loc(), xscale, yscale)
+		gr.drawCell(vs, &c.Cell, *c.Loc(), xscale, yscale, offsetX, offsetY)
+		if c.BlendMode == BlendNormal {
+			normal++
+		}
 		offset += 4
 	}
-	if target != nil {
+	if target == nil {
+		return
+	}
+	if normal == 0 {
 		target.DrawTriangles(gr.vertices, gr.indices, squareData.img, op)
+		return
+	}
+	// draw the grid and any additive cells, then draw the normal cells
+	// over them.
+	cellIndices := gr.Width * gr.Height * 6
+	target.DrawTriangles(gr.vertices, gr.indices[:cellIndices], squareData.img, op)
+	gr.addIndices, gr.normalIndices = gr.addIndices[:0], gr.normalIndices[:0]
+	for i, c := range gr.ExtraCells {
+		indices := gr.indices[cellIndices+i*6 : cellIndices+(i+1)*6]
+		if c.BlendMode == BlendNormal {
+			gr.normalIndices = append(gr.normalIndices, indices...)
+		} else {
+			gr.addIndices = append(gr.addIndices, indices...)
+		}
+	}
+	if len(gr.addIndices) > 0 {
+		target.DrawTriangles(gr.vertices, gr.addIndices, squareData.img, op)
 	}
+	target.DrawTriangles(gr.vertices, gr.normalIndices, squareData.img, &ebiten.DrawTrianglesOptions{CompositeMode: ebiten.CompositeModeSourceOver})
 }
@@ -91,7 +91,9 @@ type HexGrid struct {
 	indices             []uint16
 	hexDirs             [6][2]float32
 	ox, oy              float32 // offset to draw grid at for centering
+	sx, sy              int     // screen size
 	Status              string
+	rng                 *rand.Rand
 }
 
 type HexCell struct {
@@ -107,21 +109,27 @@ type HexMotion struct {
 
 // NewDir yields a random hex direction
 func (gr *HexGrid) NewDir() HexDir {
-	return HexDir(rand.Int31n(6))
+	return HexDir(gr.rng.Int31n(6))
 }
 
 func (gr *HexGrid) Palette() *Palette {
 	return gr.palette
 }
 
+// Rand yields the grid's random number source, which it shares with
+// the context that created it.
+func (gr *HexGrid) Rand() *rand.Rand {
+	return gr.rng
+}
+
 // RandRow yields a random valid row.
 func (gr *HexGrid) RandRow() int {
-	return int(rand.Int31n(int32(gr.Height)))
+	return int(gr.rng.Int31n(int32(gr.Height)))
 }
 
 // RandCol yields a random valid column.
 func (gr *HexGrid) RandCol() int {
-	return int(rand.Int31n(int32(gr.Width)))
+	return int(gr.rng.Int31n(int32(gr.Width)))
 }
 
 func (gr *HexGrid) NewLoc() ILoc {
@@ -134,10 +142,10 @@ func (gr *HexGrid) NewLoc() ILoc {
 // we start with the easy one: we use the flat ends, so the width of
 // the row is trivial, except we need an extra half-hex, because a second
 // row of hexes will be half a hex offset.
-func newHexGrid(w int, r RenderType, p *Palette, sx, sy int) *HexGrid {
+func newHexGrid(w int, r RenderType, p *Palette, sx, sy int, rng *rand.Rand) *HexGrid {
 	textureSetup()
 
-	gr := &HexGrid{render: r, Width: w, palette: p}
+	gr := &HexGrid{render: r, Width: w, palette: p, rng: rng, sx: sx, sy: sy}
 	var hexWidth float32
 	var hexHeight float32
 	var vHexes float32
@@ -221,14 +229,25 @@ func (gr *HexGrid) center(row, col int, scale float32) (x, y float32) {
 	return (x + gr.ox) * scale, (y + gr.oy) * scale
 }
 
+// CellAt yields the location of the hex at the given screen coordinates,
+// and the cell there, which is nil if the coordinates are outside the
+// grid.
 func (gr *HexGrid) CellAt(x, y int) (l ILoc, c *HexCell) {
-	x, y = x-int(gr.ox), y-int(gr.oy)
-	xInt, xOffset := math.Modf(float32(x) / gr.hexWidth)
-	yInt, yOffset := math.Modf(float32(y) / gr.perHexHeight)
+	return gr.cellAt(float32(x), float32(y))
+}
+
+func (gr *HexGrid) cellAt(fx, fy float32) (l ILoc, c *HexCell) {
+	fx, fy = fx-gr.ox, fy-gr.oy
+	if fx < 0 || fy < 0 {
+		// nothing is left of or above the grid's offset
+		return ILoc{X: int(math.Floor(fx / gr.hexWidth)), Y: int(math.Floor(fy / gr.perHexHeight))}, nil
+	}
+	xInt, xOffset := math.Modf(fx / gr.hexWidth)
+	yInt, yOffset := math.Modf(fy / gr.perHexHeight)
 	xOffset -= 0.5
 	xAway := math.Abs(xOffset) / 0.5
 	//	fmt.Printf("%d, %d => %.0f [%.3f] [+%.3f], %.0f [%.3f]", x, y, xInt, xOffset, xAway, yInt, yOffset)
-	x, y = int(xInt), int(yInt)
+	x, y := int(xInt), int(yInt)
 	if y%2 == 1 {
 		if yOffset < 0.33 && (1-xAway) > yOffset*3 {
 			y--
@@ -258,6 +277,24 @@ func (gr *HexGrid) CellAt(x, y int) (l ILoc, c *HexCell) {
 	}
 }
 
+// Pick yields the location and cell at the given screen coordinates.
+func (gr *HexGrid) Pick(x, y int) (ILoc, *Cell) {
+	l, c := gr.CellAt(x, y)
+	if c == nil {
+		return l, nil
+	}
+	return l, &c.Cell
+}
+
+// PickCentered yields the location and cell at the given point.
+func (gr *HexGrid) PickCentered(p Point) (ILoc, *Cell) {
+	l, c := gr.cellAt(toScreen(p, gr.sx, gr.sy))
+	if c == nil {
+		return l, nil
+	}
+	return l, &c.Cell
+}
+
 func (gr *HexGrid) Cell(x, y int) (ILoc, *HexCell) {
 	x, y = x%gr.Width, y%gr.Height
 	if x < 0 {
@@ -588,11 +588,13 @@ func (pl *PolyLine) Draw(target *ebiten.Image, alpha float32, scale float32) {
 	}
 	halfthick := thickness / 2
 	var vCount, iCount int
+	// vertices are computed at full alpha, and alpha is applied when
+	// drawing, so a fading line doesn't need to be recomputed
 	if pl.dirty {
 		if pl.Joined {
-			vCount, iCount = pl.computeJoinedVertices(halfthick, alpha, scale)
+			vCount, iCount = pl.computeJoinedVertices(halfthick, 1, scale)
 		} else {
-			vCount, iCount = pl.computeUnjoinedVertices(halfthick, alpha, scale)
+			vCount, iCount = pl.computeUnjoinedVertices(halfthick, 1, scale)
 		}
 		// trim to actually returned length
 		pl.vertices = pl.vertices[:vCount]
@@ -602,7 +604,9 @@ func (pl *PolyLine) Draw(target *ebiten.Image, alpha float32, scale float32) {
 
 	// draw the triangles
 	if target != nil {
-		target.DrawTriangles(pl.vertices, pl.indices, lineData.img, &ebiten.DrawTrianglesOptions{CompositeMode: ebiten.CompositeModeLighter})
+		opt := ebiten.DrawTrianglesOptions{CompositeMode: ebiten.CompositeModeLighter}
+		opt.ColorM.Scale(1, 1, 1, float64(alpha))
+		target.DrawTriangles(pl.vertices, pl.indices, lineData.img, &opt)
 		if pl.debug != nil {
 			pl.debug.Draw(target, alpha, scale)
 		}
@@ -2,6 +2,7 @@ package g
 
 import (
 	"image/color"
+	"sort"
 
 	"github.com/hajimehoshi/ebiten"
 )
@@ -42,6 +43,29 @@ func init() {
 	}
 }
 
+// PaletteNames yields the names of the known palettes, sorted.
+func PaletteNames() []string {
+	names := make([]string, 0, len(Palettes))
+	for name := range Palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StepPalette yields the name of the palette n places after name in
+// PaletteNames, wrapping around. An unknown name counts as being just
+// before the first palette.
+func StepPalette(name string, n int) string {
+	names := PaletteNames()
+	i := sort.SearchStrings(names, name)
+	if i == len(names) || names[i] != name {
+		i = -1
+	}
+	i = ((i+n)%len(names) + len(names)) % len(names)
+	return names[i]
+}
+
 // Initialize converts a palettes RGBA colors to ebiten.ColorM objects.
 func (p *Palette) Initialize() {
 	p.Length = len(p.RGBA)
@@ -116,3 +140,22 @@ func (p Palette) Color(pt Paint) color.Color {
 func (p Palette) Inc(pt Paint, n int) Paint {
 	return Paint(p.coerced(int(pt) + n))
 }
+
+// Toward yields the Paint up to n steps from pt towards target, going
+// whichever way around the palette is shorter. If target is within n
+// steps, it yields target.
+func (p Palette) Toward(pt, target Paint, n int) Paint {
+	from, to := p.coerced(int(pt)), p.coerced(int(target))
+	diff := to - from
+	if diff > p.Length/2 {
+		diff -= p.Length
+	} else if diff < -p.Length/2 {
+		diff += p.Length
+	}
+	if diff > n {
+		diff = n
+	} else if diff < -n {
+		diff = -n
+	}
+	return Paint(p.coerced(from + diff))
+}
@@ -0,0 +1,243 @@
+package g
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RegisterPalette initializes p and adds it to Palettes, replacing any
+// existing palette with the same name. Names can't contain whitespace,
+// because session files and playlists separate fields with it.
+func RegisterPalette(name string, p *Palette) error {
+	if name == "" {
+		return fmt.Errorf("palette has no name")
+	}
+	if hasSpace(name) {
+		return fmt.Errorf("palette name %q contains whitespace", name)
+	}
+	if len(p.RGBA) == 0 {
+		return fmt.Errorf("palette %s has no colors", name)
+	}
+	p.Initialize()
+	Palettes[name] = p
+	return nil
+}
+
+// ReadGPL reads a GIMP palette. It yields the palette's name, if the
+// file gives one.
+//
+//	GIMP Palette
+//	Name: sunset
+//	Columns: 4
+//	# comment
+//	255  94  77	Coral
+//	255 182  72
+func ReadGPL(r io.Reader) (string, *Palette, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "GIMP Palette" {
+		if err := scanner.Err(); err != nil {
+			return "", nil, err
+		}
+		return "", nil, fmt.Errorf("missing GIMP Palette header")
+	}
+	var name string
+	p := &Palette{}
+	line := 1
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "", strings.HasPrefix(text, "#"):
+			continue
+		case strings.HasPrefix(text, "Name:"):
+			name = strings.TrimSpace(text[len("Name:"):])
+			continue
+		case strings.HasPrefix(text, "Columns:"):
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 3 {
+			return "", nil, fmt.Errorf("line %d: expected red, green, and blue values", line)
+		}
+		var rgb [3]uint8
+		for i := range rgb {
+			v, err := strconv.ParseUint(fields[i], 10, 8)
+			if err != nil {
+				return "", nil, fmt.Errorf("line %d: invalid color value %q", line, fields[i])
+			}
+			rgb[i] = uint8(v)
+		}
+		p.RGBA = append(p.RGBA, color.RGBA{rgb[0], rgb[1], rgb[2], 255})
+	}
+	if err := scanner.Err(); err != nil {
+		return "", nil, err
+	}
+	return name, p, nil
+}
+
+// parseHexColor parses a color in the form rrggbb, with an optional
+// leading #.
+func parseHexColor(s string) (color.RGBA, error) {
+	h := strings.TrimPrefix(s, "#")
+	if len(h) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, expected rrggbb", s)
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, expected rrggbb", s)
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}, nil
+}
+
+// ReadHex reads a list of hex colors, one per line, with an optional
+// leading #. Blank lines, and lines starting with ; or //, are ignored.
+//
+//	; sunset
+//	ff5e4d
+//	#ffb648
+func ReadHex(r io.Reader) (*Palette, error) {
+	p := &Palette{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, ";") || strings.HasPrefix(text, "//") {
+			continue
+		}
+		c, err := parseHexColor(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		p.RGBA = append(p.RGBA, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// jsonPalette is the JSON palette format:
+//
+//	{"name": "sunset", "colors": ["#ff5e4d", "#ffb648"]}
+type jsonPalette struct {
+	Name   string   `json:"name"`
+	Colors []string `json:"colors"`
+}
+
+// ReadJSON reads a JSON palette. It yields the palette's name, if the
+// file gives one.
+func ReadJSON(r io.Reader) (string, *Palette, error) {
+	var jp jsonPalette
+	err := json.NewDecoder(r).Decode(&jp)
+	if err != nil {
+		return "", nil, err
+	}
+	p := &Palette{}
+	for i, s := range jp.Colors {
+		c, err := parseHexColor(s)
+		if err != nil {
+			return "", nil, fmt.Errorf("color %d: %v", i, err)
+		}
+		p.RGBA = append(p.RGBA, c)
+	}
+	return jp.Name, p, nil
+}
+
+// paletteExts maps file extensions to the formats LoadPalette reads.
+var paletteExts = map[string]func(io.Reader) (string, *Palette, error){
+	".gpl":  ReadGPL,
+	".json": ReadJSON,
+	".hex": func(r io.Reader) (string, *Palette, error) {
+		p, err := ReadHex(r)
+		return "", p, err
+	},
+}
+
+func hasSpace(s string) bool {
+	return strings.IndexFunc(s, unicode.IsSpace) >= 0
+}
+
+// readPaletteFile reads a palette file, choosing the format by its
+// extension, and yields the palette and the name to register it under.
+func readPaletteFile(path string) (string, *Palette, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	read := paletteExts[ext]
+	if read == nil {
+		return "", nil, fmt.Errorf("%s: unknown palette format %q", path, ext)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+	name, p, err := read(f)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %v", path, err)
+	}
+	// names like "Sunset Colors" can't be written to session files or
+	// playlists, so those fall back to the file's name
+	if name == "" || hasSpace(name) {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return name, p, nil
+}
+
+// LoadPalette reads a palette file, choosing the format by its extension:
+// .gpl for GIMP palettes, .hex for hex color lists, and .json for JSON
+// palettes. It registers the palette under the name the file gives, or,
+// if it gives none or one with whitespace in it, the file's name without
+// the extension, and yields that name.
+func LoadPalette(path string) (string, error) {
+	name, p, err := readPaletteFile(path)
+	if err != nil {
+		return "", err
+	}
+	err = RegisterPalette(name, p)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", path, err)
+	}
+	return name, nil
+}
+
+// LoadPaletteDir loads every palette file in dir, skipping files it
+// doesn't recognize, and yields the names of the palettes it loaded. It's
+// an error for a file to use the name of a palette which already exists,
+// whether built in or from another file.
+func LoadPaletteDir(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || paletteExts[strings.ToLower(filepath.Ext(e.Name()))] == nil {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		name, p, err := readPaletteFile(path)
+		if err != nil {
+			return names, err
+		}
+		if Palettes[name] != nil {
+			return names, fmt.Errorf("%s: palette %s already exists", path, name)
+		}
+		err = RegisterPalette(name, p)
+		if err != nil {
+			return names, fmt.Errorf("%s: %v", path, err)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,174 @@
+package g_test
+
+import (
+	"bytes"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+)
+
+var testPaletteColors = []color.RGBA{
+	{255, 94, 77, 255},
+	{255, 182, 72, 255},
+}
+
+func checkPalette(t *testing.T, format string, p *g.Palette) {
+	if len(p.RGBA) != len(testPaletteColors) {
+		t.Fatalf("%s: expected %d colors, got %d", format, len(testPaletteColors), len(p.RGBA))
+	}
+	for i, c := range testPaletteColors {
+		if p.RGBA[i] != c {
+			t.Errorf("%s: color %d: expected %v, got %v", format, i, c, p.RGBA[i])
+		}
+	}
+}
+
+func TestReadPalettes(t *testing.T) {
+	name, p, err := g.ReadGPL(strings.NewReader("GIMP Palette\nName: sunset\nColumns: 2\n# comment\n255  94  77\tCoral\n255 182  72\n"))
+	if err != nil {
+		t.Fatalf("gpl: unexpected error: %v", err)
+	}
+	if name != "sunset" {
+		t.Errorf("gpl: expected name sunset, got %q", name)
+	}
+	checkPalette(t, "gpl", p)
+
+	p, err = g.ReadHex(strings.NewReader("; sunset\nff5e4d\n\n#FFB648\n"))
+	if err != nil {
+		t.Fatalf("hex: unexpected error: %v", err)
+	}
+	checkPalette(t, "hex", p)
+
+	name, p, err = g.ReadJSON(strings.NewReader(`{"name": "sunset", "colors": ["#ff5e4d", "ffb648"]}`))
+	if err != nil {
+		t.Fatalf("json: unexpected error: %v", err)
+	}
+	if name != "sunset" {
+		t.Errorf("json: expected name sunset, got %q", name)
+	}
+	checkPalette(t, "json", p)
+
+	if _, _, err = g.ReadGPL(strings.NewReader("255 0 0\n")); err == nil {
+		t.Errorf("gpl without header: expected error, got none")
+	}
+	if _, _, err = g.ReadGPL(strings.NewReader("GIMP Palette\n256 0 0\n")); err == nil {
+		t.Errorf("gpl with out-of-range value: expected error, got none")
+	}
+	if _, err = g.ReadHex(strings.NewReader("ff5e4\n")); err == nil {
+		t.Errorf("hex with short color: expected error, got none")
+	}
+}
+
+func TestLoadPaletteDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "palettes")
+	if err != nil {
+		t.Fatalf("creating directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"gpl.gpl":    "GIMP Palette\nName: named\n255 94 77\n255 182 72\n",
+		"hexes.hex":  "ff5e4d\nffb648\n",
+		"readme.txt": "not a palette",
+	}
+	for name, contents := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644)
+		if err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	names, err := g.LoadPaletteDir(dir)
+	if err != nil {
+		t.Fatalf("loading palettes: unexpected error: %v", err)
+	}
+	if strings.Join(names, ",") != "hexes,named" {
+		t.Errorf("expected palettes hexes,named, got %v", names)
+	}
+	for _, name := range names {
+		p := g.Palettes[name]
+		if p == nil {
+			t.Fatalf("palette %s not registered", name)
+		}
+		if p.Length != len(testPaletteColors) {
+			t.Errorf("palette %s: expected initialized length %d, got %d", name, len(testPaletteColors), p.Length)
+		}
+		checkPalette(t, name, p)
+		delete(g.Palettes, name)
+	}
+}
+
+func TestLoadPaletteSpacedName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "palettes")
+	if err != nil {
+		t.Fatalf("creating directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sunset.gpl")
+	err = ioutil.WriteFile(path, []byte("GIMP Palette\nName: Sunset Colors\n255 94 77\n255 182 72\n"), 0644)
+	if err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+	name, err := g.LoadPalette(path)
+	if err != nil {
+		t.Fatalf("loading palette: unexpected error: %v", err)
+	}
+	defer delete(g.Palettes, name)
+	if name != "sunset" {
+		t.Errorf("expected spaced name to fall back to sunset, got %q", name)
+	}
+
+	// the name has to survive a trip through a session file
+	var buf bytes.Buffer
+	start := keys.ModeSwitch{Name: "lines", Detail: 10, Palette: name}
+	rec := keys.NewRecorder(&buf, 1, start)
+	if err := rec.Close(); err != nil {
+		t.Fatalf("recording session: unexpected error: %v", err)
+	}
+	rp, err := keys.ReadReplay(&buf)
+	if err != nil {
+		t.Fatalf("reading session: unexpected error: %v", err)
+	}
+	if rp.Start != start {
+		t.Errorf("expected session to start with %v, got %v", start, rp.Start)
+	}
+
+	if err := g.RegisterPalette("Sunset Colors", &g.Palette{RGBA: testPaletteColors}); err == nil {
+		t.Errorf("registering spaced name: expected error, got none")
+	}
+}
+
+func TestLoadPaletteDirDuplicates(t *testing.T) {
+	for _, files := range []map[string]string{
+		{"rainbow.hex": "ff5e4d\n"},
+		{"foo.gpl": "GIMP Palette\n255 94 77\n", "foo.hex": "ff5e4d\n"},
+		{"a.gpl": "GIMP Palette\nName: same\n255 94 77\n", "b.gpl": "GIMP Palette\nName: same\n255 94 77\n"},
+	} {
+		dir, err := ioutil.TempDir("", "palettes")
+		if err != nil {
+			t.Fatalf("creating directory: %v", err)
+		}
+		for name, contents := range files {
+			err = ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644)
+			if err != nil {
+				t.Fatalf("writing %s: %v", name, err)
+			}
+		}
+		rainbow := g.Palettes["rainbow"]
+		names, err := g.LoadPaletteDir(dir)
+		if err == nil {
+			t.Errorf("%v: expected duplicate name error, got none", files)
+		}
+		if g.Palettes["rainbow"] != rainbow {
+			t.Errorf("%v: built-in rainbow palette was replaced", files)
+		}
+		for _, name := range names {
+			delete(g.Palettes, name)
+		}
+		os.RemoveAll(dir)
+	}
+}
@@ -0,0 +1,53 @@
+package g_test
+
+import (
+	"testing"
+
+	"seebs.net/modus/g"
+)
+
+func TestPaletteToward(t *testing.T) {
+	p := g.Palettes["rainbow"].Interpolate(4)
+	cases := []struct {
+		from, to g.Paint
+		n        int
+		expected g.Paint
+	}{
+		{from: 0, to: 3, n: 1, expected: 1},
+		{from: 0, to: 3, n: 5, expected: 3},
+		{from: 3, to: 0, n: 1, expected: 2},
+		{from: 1, to: 22, n: 1, expected: 0},
+		{from: 0, to: 22, n: 1, expected: 23},
+		{from: 23, to: 2, n: 2, expected: 1},
+		{from: 5, to: 5, n: 1, expected: 5},
+		{from: 29, to: 2, n: 1, expected: 4},
+	}
+	for _, c := range cases {
+		got := p.Toward(c.from, c.to, c.n)
+		if got != c.expected {
+			t.Errorf("%d toward %d by %d: expected %d, got %d", c.from, c.to, c.n, c.expected, got)
+		}
+	}
+}
+
+func TestStepPalette(t *testing.T) {
+	names := g.PaletteNames()
+	for i, name := range names {
+		next := g.StepPalette(name, 1)
+		if next != names[(i+1)%len(names)] {
+			t.Errorf("StepPalette(%s, 1): expected %s, got %s", name, names[(i+1)%len(names)], next)
+		}
+		if prev := g.StepPalette(next, -1); prev != name {
+			t.Errorf("StepPalette(%s, -1): expected %s, got %s", next, name, prev)
+		}
+	}
+	// unknown names, wherever they'd sort, count as before the first
+	for _, unknown := range []string{"", names[len(names)/2] + "-unknown", "~"} {
+		if first := g.StepPalette(unknown, 1); first != names[0] {
+			t.Errorf("StepPalette(%q, 1): expected %s, got %s", unknown, names[0], first)
+		}
+		if last := g.StepPalette(unknown, 0); last != names[len(names)-1] {
+			t.Errorf("StepPalette(%q, 0): expected %s, got %s", unknown, names[len(names)-1], last)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package g_test
+
+import (
+	"testing"
+
+	"seebs.net/modus/g"
+)
+
+func TestSquarePick(t *testing.T) {
+	// 1000/30 => 33 pixel squares, 21 rows, leaving a 5x3 pixel border
+	tests := []struct {
+		x, y   int
+		l      g.ILoc
+		inGrid bool
+	}{
+		{x: 2, y: 2, inGrid: false},
+		{x: 6, y: 4, l: g.ILoc{X: 0, Y: 0}, inGrid: true},
+		{x: 37, y: 35, l: g.ILoc{X: 0, Y: 0}, inGrid: true},
+		{x: 39, y: 37, l: g.ILoc{X: 1, Y: 1}, inGrid: true},
+		{x: 994, y: 695, l: g.ILoc{X: 29, Y: 20}, inGrid: true},
+		{x: 996, y: 697, inGrid: false},
+	}
+	for _, multisample := range []bool{false, true} {
+		c := g.NewContext(1000, 700, multisample)
+		gr := c.NewSquareGrid(30, 1, g.Palettes["rainbow"])
+		for _, test := range tests {
+			l, cell := gr.Pick(test.x, test.y)
+			if (cell != nil) != test.inGrid || (test.inGrid && l != test.l) {
+				t.Errorf("multisample %t: %d,%d: expected %v/%t, got %v/%t", multisample, test.x, test.y, test.l, test.inGrid, l, cell != nil)
+			}
+			cl, ccell := gr.PickCentered(c.FromScreen(test.x, test.y))
+			if cl != l || ccell != cell {
+				t.Errorf("multisample %t: %d,%d: centered pick got %v, expected %v", multisample, test.x, test.y, cl, l)
+			}
+		}
+	}
+}
+
+func TestHexPick(t *testing.T) {
+	c := g.NewContext(1280, 960, true)
+	gr := c.NewHexGrid(20, 1, g.Palettes["rainbow"])
+	var grid g.Grid = gr
+	for x := 0; x < gr.Width; x++ {
+		for y := 0; y < gr.Height; y++ {
+			cx, cy := gr.CenterFor(y, x)
+			l, cell := grid.Pick(int(cx), int(cy))
+			if cell == nil || l.X != x || l.Y != y {
+				t.Errorf("hex %d,%d: picked %v at its center", x, y, l)
+			}
+			if cl, _ := grid.PickCentered(c.FromScreen(int(cx), int(cy))); cl != l {
+				t.Errorf("hex %d,%d: centered pick got %v, expected %v", x, y, cl, l)
+			}
+		}
+	}
+	if _, cell := grid.Pick(0, 0); cell != nil {
+		t.Errorf("expected no hex in the corner of the screen")
+	}
+}
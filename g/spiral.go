@@ -1,6 +1,8 @@
 package g
 
 import (
+	"math/rand"
+
 	math "github.com/chewxy/math32"
 
 	"github.com/hajimehoshi/ebiten"
@@ -22,6 +24,7 @@ type Spiral struct {
 	sprite         *Sprite
 	scaleTheta     float32
 	thetaRatio     float32
+	rng            *rand.Rand
 }
 
 // the ripple pattern is used to perturb the radius of a spiral to make it look
@@ -30,8 +33,8 @@ var ripplePattern = []int{-1, -2, 0, 2, 1, 0, -1, 0, 1}
 var defaultThetaRatio = float32(4.0)
 
 // newSpiral creates a new spiral.
-func newSpiral(depth int, r RenderType, points int, p *Palette, cycles int, offset int, scale, offsetX, offsetY float32) *Spiral {
-	s := &Spiral{Depth: depth, render: r, Length: points, Step: 1}
+func newSpiral(depth int, r RenderType, points int, p *Palette, cycles int, offset int, scale, offsetX, offsetY float32, rng *rand.Rand) *Spiral {
+	s := &Spiral{Depth: depth, render: r, Length: points, Step: 1, rng: rng}
 	// we want to make it through the palette cycles times; for instance,
 	// if cycles is 3, we want a total of 18 color shifts, divided among
 	// s.Length segments, so that's the interpolation scale.
@@ -119,7 +122,7 @@ func (s *Spiral) Compute(pl *PolyLine) {
 func (s *Spiral) Update() (bounced bool, note int, l Point) {
 	if s.Target.Update() {
 		s.Ripples = append(s.Ripples, s.Length)
-		s.Target.PerturbVelocity()
+		s.Target.PerturbVelocity(s.rng)
 		bounced = true
 	}
 	// emit note/color even if we won't play it
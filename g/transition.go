@@ -0,0 +1,98 @@
+package g
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// TransitionType is a way of blending from one image to another.
+type TransitionType int
+
+const (
+	// Crossfade fades the new image in over the old one.
+	Crossfade TransitionType = iota
+	// Wipe reveals the new image from left to right.
+	Wipe
+	// ZoomDissolve zooms into the old image while it dissolves into
+	// the new one.
+	ZoomDissolve
+)
+
+var transitionNames = []string{"crossfade", "wipe", "zoom"}
+
+func (t TransitionType) String() string {
+	if int(t) < len(transitionNames) {
+		return transitionNames[t]
+	}
+	return "unknown"
+}
+
+// ParseTransitionType yields the transition type with the given name.
+func ParseTransitionType(name string) (TransitionType, error) {
+	for i, n := range transitionNames {
+		if n == name {
+			return TransitionType(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown transition %q (known: %v)", name, transitionNames)
+}
+
+// zoomScale is how far the outgoing image is zoomed by the end of a
+// ZoomDissolve.
+const zoomScale = 1.5
+
+// NewScreenImage creates an offscreen image the size of the context's
+// screen, which a scene can draw to in place of the screen.
+func (c *Context) NewScreenImage() (*ebiten.Image, error) {
+	return ebiten.NewImage(c.w, c.h, ebiten.FilterLinear)
+}
+
+// ClearScreenImage clears an image from NewScreenImage to opaque black,
+// so a scene drawn on it looks as it would on the screen.
+func (c *Context) ClearScreenImage(img *ebiten.Image) error {
+	return img.Fill(color.Black)
+}
+
+// DrawTransition draws a transition from one screen image to another,
+// at a given progress from 0 (entirely from) to 1 (entirely to).
+func (c *Context) DrawTransition(screen, from, to *ebiten.Image, tt TransitionType, progress float32) {
+	if progress < 0 {
+		progress = 0
+	}
+	if progress > 1 {
+		progress = 1
+	}
+	c.Render(screen, func(target *ebiten.Image, scale float32) {
+		op := &ebiten.DrawImageOptions{}
+		switch tt {
+		case Wipe:
+			op.GeoM.Scale(float64(scale), float64(scale))
+			target.DrawImage(from, op)
+			edge := int(progress * float32(c.w))
+			if edge > 0 {
+				op.SourceRect = &image.Rectangle{Max: image.Point{X: edge, Y: c.h}}
+				target.DrawImage(to, op)
+			}
+		case ZoomDissolve:
+			op.GeoM.Scale(float64(scale), float64(scale))
+			target.DrawImage(to, op)
+			// zoom the outgoing image around the center of the screen
+			z := 1 + (zoomScale-1)*float64(progress)
+			op.GeoM.Reset()
+			op.GeoM.Translate(-float64(c.w)/2, -float64(c.h)/2)
+			op.GeoM.Scale(z, z)
+			op.GeoM.Translate(float64(c.w)/2, float64(c.h)/2)
+			op.GeoM.Scale(float64(scale), float64(scale))
+			op.ColorM.Scale(1, 1, 1, float64(1-progress))
+			target.DrawImage(from, op)
+		default:
+			op.GeoM.Scale(float64(scale), float64(scale))
+			target.DrawImage(from, op)
+			op.ColorM.Scale(1, 1, 1, float64(progress))
+			target.DrawImage(to, op)
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package g_test
+
+import (
+	"testing"
+
+	"seebs.net/modus/g"
+)
+
+func TestParseTransitionType(t *testing.T) {
+	for _, tt := range []g.TransitionType{g.Crossfade, g.Wipe, g.ZoomDissolve} {
+		parsed, err := g.ParseTransitionType(tt.String())
+		if err != nil || parsed != tt {
+			t.Errorf("%s: expected %d, got %d, error %v", tt, tt, parsed, err)
+		}
+	}
+	if _, err := g.ParseTransitionType("nosuchtransition"); err == nil {
+		t.Errorf("expected error for unknown transition, got none")
+	}
+}
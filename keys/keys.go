@@ -94,12 +94,24 @@ func (km Map) AllUp(ks ...ebiten.Key) bool {
 	return true
 }
 
+// Update updates the key states from the keyboard.
 func (km Map) Update() {
+	km.UpdateFrom(Ebiten)
+}
+
+// UpdateFrom advances src to its next frame, and updates the key states
+// from it. It reports false, leaving the key states alone, if src has no
+// more frames.
+func (km Map) UpdateFrom(src Source) bool {
+	if !src.Next() {
+		return false
+	}
 	for i := range km {
 		state := byte(0)
-		if ebiten.IsKeyPressed(i) {
+		if src.Down(i) {
 			state = 1
 		}
 		km[i] = ((km[i] & 0x1) << 1) | state
 	}
+	return true
 }
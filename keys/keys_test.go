@@ -0,0 +1,41 @@
+package keys_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/keys"
+)
+
+func TestScript(t *testing.T) {
+	script := keys.NewScript(
+		nil,
+		[]ebiten.Key{ebiten.KeyA},
+		[]ebiten.Key{ebiten.KeyA},
+		nil,
+		nil,
+	)
+	expected := []struct {
+		pressed, held, released, down bool
+	}{
+		{false, false, false, false},
+		{true, false, false, true},
+		{false, true, false, true},
+		{false, false, true, false},
+		{false, false, false, false},
+	}
+	km := keys.NewMap(ebiten.KeyA)
+	for i, e := range expected {
+		if !km.UpdateFrom(script) {
+			t.Fatalf("script ended early, at frame %d", i)
+		}
+		pressed, held, released, down := km.Pressed(ebiten.KeyA), km.Held(ebiten.KeyA), km.Released(ebiten.KeyA), km.Down(ebiten.KeyA)
+		if pressed != e.pressed || held != e.held || released != e.released || down != e.down {
+			t.Errorf("frame %d: expected pressed/held/released/down %t/%t/%t/%t, got %t/%t/%t/%t",
+				i, e.pressed, e.held, e.released, e.down, pressed, held, released, down)
+		}
+	}
+	if km.UpdateFrom(script) {
+		t.Errorf("expected script to end after %d frames", len(expected))
+	}
+}
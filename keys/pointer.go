@@ -0,0 +1,119 @@
+package keys
+
+import (
+	"sort"
+
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+)
+
+// MouseID is the ID used for the left mouse button, which is treated as
+// one more touch.
+const MouseID = -1
+
+// Pointer is the state of a single touch, or of the mouse button. Its
+// State uses the same PRESS/HOLD/RELEASE values as key states; a pointer
+// is reported once with RELEASE after it lifts, then dropped.
+type Pointer struct {
+	ID     int
+	State  byte
+	X, Y   int     // screen coordinates
+	Loc    g.Point // location in the context's Centered coordinates
+	Prev   g.Point // location on the previous frame
+	Start  g.Point // location where the pointer was first pressed
+	SX, SY int     // screen coordinates where the pointer was first pressed
+}
+
+// Pressed reports whether the pointer went down this frame.
+func (p *Pointer) Pressed() bool {
+	return p.State == PRESS
+}
+
+// Held reports whether the pointer was down on both this frame and the
+// previous one.
+func (p *Pointer) Held() bool {
+	return p.State == HOLD
+}
+
+// Released reports whether the pointer lifted this frame.
+func (p *Pointer) Released() bool {
+	return p.State == RELEASE
+}
+
+// Down reports whether the pointer is currently down.
+func (p *Pointer) Down() bool {
+	return p.State&PRESS != 0
+}
+
+// Dragged reports whether the pointer moved while held down.
+func (p *Pointer) Dragged() bool {
+	return p.State == HOLD && p.Loc != p.Prev
+}
+
+// Pointers is the state of every current pointer, sorted by ID.
+type Pointers []Pointer
+
+// ScreenPoint is the screen location of a pointer which is down.
+type ScreenPoint struct {
+	X, Y int
+}
+
+// ScreenPointers yields the screen locations of the mouse, if its left
+// button is down, and of any touches.
+func ScreenPointers() map[int]ScreenPoint {
+	down := make(map[int]ScreenPoint)
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		down[MouseID] = ScreenPoint{X: x, Y: y}
+	}
+	for _, id := range ebiten.TouchIDs() {
+		x, y := ebiten.TouchPosition(id)
+		down[id] = ScreenPoint{X: x, Y: y}
+	}
+	return down
+}
+
+// Track updates the pointers given the screen locations of every pointer
+// which is currently down. Pointers which were down, but aren't in down,
+// are released; pointers which were released on the previous frame are
+// dropped.
+func (ps *Pointers) Track(gctx *g.Context, down map[int]ScreenPoint) {
+	n := 0
+	seen := make(map[int]bool, len(down))
+	for _, p := range *ps {
+		if p.State == RELEASE {
+			continue
+		}
+		p.Prev = p.Loc
+		if sp, ok := down[p.ID]; ok {
+			p.X, p.Y = sp.X, sp.Y
+			p.Loc = gctx.FromScreen(sp.X, sp.Y)
+			p.State = HOLD
+		} else {
+			p.State = RELEASE
+		}
+		seen[p.ID] = true
+		(*ps)[n] = p
+		n++
+	}
+	*ps = (*ps)[:n]
+	for id, sp := range down {
+		if seen[id] {
+			continue
+		}
+		loc := gctx.FromScreen(sp.X, sp.Y)
+		*ps = append(*ps, Pointer{ID: id, State: PRESS, X: sp.X, Y: sp.Y, SX: sp.X, SY: sp.Y, Loc: loc, Prev: loc, Start: loc})
+	}
+	sort.Slice(*ps, func(i, j int) bool { return (*ps)[i].ID < (*ps)[j].ID })
+}
+
+// Down yields the pointers which are currently down.
+func (ps Pointers) Down() Pointers {
+	var down Pointers
+	for _, p := range ps {
+		if p.Down() {
+			down = append(down, p)
+		}
+	}
+	return down
+}
@@ -0,0 +1,40 @@
+package keys_test
+
+import (
+	"testing"
+
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+)
+
+func TestPointers(t *testing.T) {
+	c := g.NewContext(400, 200, false)
+	var ps keys.Pointers
+	frames := []struct {
+		down     map[int]keys.ScreenPoint
+		expected []byte // states, in ID order
+	}{
+		{map[int]keys.ScreenPoint{3: {X: 200, Y: 100}}, []byte{keys.PRESS}},
+		{map[int]keys.ScreenPoint{3: {X: 300, Y: 100}, keys.MouseID: {X: 0, Y: 0}}, []byte{keys.PRESS, keys.HOLD}},
+		{map[int]keys.ScreenPoint{keys.MouseID: {X: 0, Y: 0}}, []byte{keys.HOLD, keys.RELEASE}},
+		{nil, []byte{keys.RELEASE}},
+		{nil, nil},
+	}
+	for i, f := range frames {
+		ps.Track(c, f.down)
+		if len(ps) != len(f.expected) {
+			t.Fatalf("frame %d: expected %d pointers, got %d", i, len(f.expected), len(ps))
+		}
+		for j, p := range ps {
+			if p.State != f.expected[j] {
+				t.Errorf("frame %d: pointer %d: expected state %d, got %d", i, p.ID, f.expected[j], p.State)
+			}
+		}
+		if i == 2 {
+			p := ps[1]
+			if p.Start != (g.Point{X: 0, Y: 0}) || p.Loc != (g.Point{X: 1, Y: 0}) || p.SX != 200 || p.X != 300 {
+				t.Errorf("released pointer: expected start 0,0 and location 1,0, got %v and %v", p.Start, p.Loc)
+			}
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package keys
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// A session file is a line-oriented text file. It starts with the
+// random seed and the first mode, with its detail level and palette:
+//
+//	seed 12345
+//	start knights1 20 rainbow
+//
+// followed by events, each tagged with the frame it happened on. A keys
+// line gives the complete set of keys held down as of that frame, and is
+// only written when that set changes. A ptr line likewise gives the ID
+// and screen location of every pointer down as of that frame. A mode line
+// records a switch to a new mode, likewise with its detail level and
+// palette. The end line gives the total number of frames recorded.
+//
+//	keys 30 23 31
+//	ptr 35 -1 640 480
+//	keys 42
+//	ptr 44
+//	mode 97 knights2 10 grey
+//	end 300
+
+// ModeSwitch is a switch to a mode, with the detail level and palette it
+// starts with. A zero Detail, or empty Palette, means the default.
+type ModeSwitch struct {
+	Name    string
+	Detail  int
+	Palette string
+}
+
+func (ms ModeSwitch) String() string {
+	palette := ms.Palette
+	if palette == "" {
+		palette = "-"
+	}
+	return fmt.Sprintf("%s %d %s", ms.Name, ms.Detail, palette)
+}
+
+// parseModeSwitch parses the fields written by ModeSwitch.String. For
+// older session files, the detail level and palette may be omitted.
+func parseModeSwitch(fields []string) (ModeSwitch, error) {
+	var ms ModeSwitch
+	if len(fields) != 1 && len(fields) != 3 {
+		return ms, fmt.Errorf("expected mode name, detail, and palette")
+	}
+	ms.Name = fields[0]
+	if len(fields) == 3 {
+		var err error
+		ms.Detail, err = strconv.Atoi(fields[1])
+		if err != nil {
+			return ms, err
+		}
+		if fields[2] != "-" {
+			ms.Palette = fields[2]
+		}
+	}
+	return ms, nil
+}
+
+// Recorder writes a session file, recording the keys held down on each
+// frame, and any mode switches.
+type Recorder struct {
+	w     *bufio.Writer
+	frame int // current frame, -1 before the first frame
+	down  []ebiten.Key
+	ptrs  map[int]ScreenPoint
+	err   error
+}
+
+// NewRecorder creates a Recorder writing to w, starting with the given
+// seed and mode.
+func NewRecorder(w io.Writer, seed int64, start ModeSwitch) *Recorder {
+	r := &Recorder{w: bufio.NewWriter(w), frame: -1}
+	r.printf("seed %d\nstart %s\n", seed, start)
+	return r
+}
+
+func (r *Recorder) printf(format string, args ...interface{}) {
+	if r.err != nil {
+		return
+	}
+	_, r.err = fmt.Fprintf(r.w, format, args...)
+}
+
+// Record starts a new frame, recording the keys down in km if they've
+// changed since the previous frame.
+func (r *Recorder) Record(km Map) error {
+	r.frame++
+	var down []ebiten.Key
+	for k, state := range km {
+		if state&PRESS != 0 {
+			down = append(down, k)
+		}
+	}
+	sort.Slice(down, func(i, j int) bool { return down[i] < down[j] })
+	if r.frame > 0 && equalKeys(down, r.down) {
+		return r.err
+	}
+	r.down = down
+	r.printf("keys %d", r.frame)
+	for _, k := range down {
+		r.printf(" %d", k)
+	}
+	r.printf("\n")
+	return r.err
+}
+
+// Pointers records the pointers down during the current frame, if they've
+// changed since the previous frame. It should be called after Record.
+func (r *Recorder) Pointers(down map[int]ScreenPoint) error {
+	if equalPointers(down, r.ptrs) {
+		return r.err
+	}
+	r.ptrs = make(map[int]ScreenPoint, len(down))
+	ids := make([]int, 0, len(down))
+	for id, sp := range down {
+		r.ptrs[id] = sp
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	frame := r.frame
+	if frame < 0 {
+		frame = 0
+	}
+	r.printf("ptr %d", frame)
+	for _, id := range ids {
+		r.printf(" %d %d %d", id, down[id].X, down[id].Y)
+	}
+	r.printf("\n")
+	return r.err
+}
+
+// Mode records a mode switch during the current frame.
+func (r *Recorder) Mode(ms ModeSwitch) error {
+	frame := r.frame
+	if frame < 0 {
+		frame = 0
+	}
+	r.printf("mode %d %s\n", frame, ms)
+	return r.err
+}
+
+// Close writes the total number of frames recorded, and flushes the
+// output. It does not close the underlying writer.
+func (r *Recorder) Close() error {
+	r.printf("end %d\n", r.frame+1)
+	if r.err != nil {
+		return r.err
+	}
+	return r.w.Flush()
+}
+
+func equalKeys(a, b []ebiten.Key) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalPointers(a, b map[int]ScreenPoint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, sp := range a {
+		if osp, ok := b[id]; !ok || osp != sp {
+			return false
+		}
+	}
+	return true
+}
+
+type replayEvent struct {
+	frame int
+	kind  string // keys, ptr, or mode
+	mode  ModeSwitch
+	down  []ebiten.Key
+	ptrs  map[int]ScreenPoint
+}
+
+// Replay is a Source which plays back a session file in place of live
+// input.
+type Replay struct {
+	Seed   int64
+	Start  ModeSwitch // the first mode
+	events []replayEvent
+	end    int // total frames, or -1 if unknown
+	frame  int
+	down   map[ebiten.Key]bool
+	ptrs   map[int]ScreenPoint
+	mode   ModeSwitch // mode switched to during the current frame
+}
+
+// ReadReplay reads a session file written by a Recorder.
+func ReadReplay(r io.Reader) (*Replay, error) {
+	rp := &Replay{end: -1, frame: -1, down: make(map[ebiten.Key]bool)}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var err error
+		switch fields[0] {
+		case "seed":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: expected seed value", line)
+			}
+			rp.Seed, err = strconv.ParseInt(fields[1], 10, 64)
+		case "start":
+			rp.Start, err = parseModeSwitch(fields[1:])
+		case "end":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: expected frame count", line)
+			}
+			rp.end, err = strconv.Atoi(fields[1])
+		case "keys", "ptr", "mode":
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: expected frame number", line)
+			}
+			ev := replayEvent{kind: fields[0]}
+			ev.frame, err = strconv.Atoi(fields[1])
+			if err != nil {
+				break
+			}
+			switch ev.kind {
+			case "mode":
+				ev.mode, err = parseModeSwitch(fields[2:])
+			case "ptr":
+				if len(fields)%3 != 2 {
+					return nil, fmt.Errorf("line %d: expected pointer ID, x, and y", line)
+				}
+				ev.ptrs = make(map[int]ScreenPoint, len(fields)/3)
+				for i := 2; i < len(fields) && err == nil; i += 3 {
+					var v [3]int
+					for j := range v {
+						v[j], err = strconv.Atoi(fields[i+j])
+						if err != nil {
+							break
+						}
+					}
+					ev.ptrs[v[0]] = ScreenPoint{X: v[1], Y: v[2]}
+				}
+			default:
+				for _, f := range fields[2:] {
+					var k int
+					k, err = strconv.Atoi(f)
+					if err != nil {
+						break
+					}
+					ev.down = append(ev.down, ebiten.Key(k))
+				}
+			}
+			rp.events = append(rp.events, ev)
+		default:
+			return nil, fmt.Errorf("line %d: unknown event %q", line, fields[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if rp.Start.Name == "" {
+		return nil, fmt.Errorf("no starting mode in session file")
+	}
+	return rp, nil
+}
+
+// Next advances to the next recorded frame, reporting false once every
+// recorded frame has been played back.
+func (rp *Replay) Next() bool {
+	if rp.end >= 0 && rp.frame+1 >= rp.end {
+		return false
+	}
+	if rp.end < 0 && len(rp.events) == 0 {
+		return false
+	}
+	rp.frame++
+	rp.mode = ModeSwitch{}
+	for len(rp.events) > 0 && rp.events[0].frame <= rp.frame {
+		ev := rp.events[0]
+		rp.events = rp.events[1:]
+		switch ev.kind {
+		case "mode":
+			rp.mode = ev.mode
+		case "ptr":
+			rp.ptrs = ev.ptrs
+		default:
+			rp.down = make(map[ebiten.Key]bool, len(ev.down))
+			for _, k := range ev.down {
+				rp.down[k] = true
+			}
+		}
+	}
+	return true
+}
+
+// Down reports whether k was down in the current frame.
+func (rp *Replay) Down(k ebiten.Key) bool {
+	return rp.down[k]
+}
+
+// Pointers yields the screen locations of the pointers down in the current
+// frame, for use with Pointers.Track.
+func (rp *Replay) Pointers() map[int]ScreenPoint {
+	return rp.ptrs
+}
+
+// Mode yields the mode to switch to, if the recorded session switched
+// modes during the current frame, and otherwise a ModeSwitch with no Name.
+func (rp *Replay) Mode() ModeSwitch {
+	return rp.mode
+}
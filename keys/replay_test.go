@@ -0,0 +1,126 @@
+package keys_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/keys"
+)
+
+func TestReplay(t *testing.T) {
+	// keys held down on each frame
+	frames := [][]ebiten.Key{
+		nil,
+		{ebiten.KeyA},
+		{ebiten.KeyA},
+		{ebiten.KeyA, ebiten.KeyS},
+		nil,
+		{ebiten.KeyUp},
+		nil,
+	}
+	var buf bytes.Buffer
+	km := keys.NewMap(ebiten.KeyA, ebiten.KeyS, ebiten.KeyUp)
+	first := keys.ModeSwitch{Name: "first", Detail: 10, Palette: "grey"}
+	second := keys.ModeSwitch{Name: "second"}
+	rec := keys.NewRecorder(&buf, 23, first)
+	script := keys.NewScript(frames...)
+	var recorded []byte
+	for km.UpdateFrom(script) {
+		if err := rec.Record(km); err != nil {
+			t.Fatalf("recording: unexpected error: %v", err)
+		}
+		if km.Pressed(ebiten.KeyUp) {
+			if err := rec.Mode(second); err != nil {
+				t.Fatalf("recording: unexpected error: %v", err)
+			}
+		}
+		recorded = append(recorded, km.State(ebiten.KeyA), km.State(ebiten.KeyS))
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("recording: unexpected error: %v", err)
+	}
+
+	rp, err := keys.ReadReplay(&buf)
+	if err != nil {
+		t.Fatalf("reading session: unexpected error: %v", err)
+	}
+	if rp.Seed != 23 || rp.Start != first {
+		t.Errorf("expected seed 23, mode %v, got seed %d, mode %v", first, rp.Seed, rp.Start)
+	}
+	km = keys.NewMap(ebiten.KeyA, ebiten.KeyS, ebiten.KeyUp)
+	var replayed []byte
+	for i := range frames {
+		if !km.UpdateFrom(rp) {
+			t.Fatalf("replay ended early, at frame %d", i)
+		}
+		mode := rp.Mode()
+		if (mode.Name != "") != (i == 5) {
+			t.Errorf("frame %d: unexpected mode switch %v", i, mode)
+		}
+		if i == 5 && mode != second {
+			t.Errorf("frame %d: expected mode switch %v, got %v", i, second, mode)
+		}
+		replayed = append(replayed, km.State(ebiten.KeyA), km.State(ebiten.KeyS))
+	}
+	if !bytes.Equal(recorded, replayed) {
+		t.Errorf("key states differ: recorded %v, replayed %v", recorded, replayed)
+	}
+	if km.UpdateFrom(rp) {
+		t.Errorf("expected replay to end after %d frames", len(frames))
+	}
+
+	// session files without detail levels and palettes still load
+	rp, err = keys.ReadReplay(strings.NewReader("seed 1\nstart first\nmode 2 second\nend 3\n"))
+	if err != nil {
+		t.Fatalf("reading old session: unexpected error: %v", err)
+	}
+	if rp.Start != (keys.ModeSwitch{Name: "first"}) {
+		t.Errorf("old session: expected mode first, got %v", rp.Start)
+	}
+}
+
+func TestReplayPointers(t *testing.T) {
+	// pointers down on each frame
+	frames := []map[int]keys.ScreenPoint{
+		nil,
+		{keys.MouseID: {X: 10, Y: 20}},
+		{keys.MouseID: {X: 10, Y: 20}},
+		{keys.MouseID: {X: 15, Y: 20}, 3: {X: 100, Y: 200}},
+		nil,
+	}
+	var buf bytes.Buffer
+	km := keys.NewMap(ebiten.KeyA)
+	rec := keys.NewRecorder(&buf, 23, keys.ModeSwitch{Name: "first"})
+	for _, down := range frames {
+		if err := rec.Record(km); err != nil {
+			t.Fatalf("recording: unexpected error: %v", err)
+		}
+		if err := rec.Pointers(down); err != nil {
+			t.Fatalf("recording: unexpected error: %v", err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("recording: unexpected error: %v", err)
+	}
+
+	rp, err := keys.ReadReplay(&buf)
+	if err != nil {
+		t.Fatalf("reading session: unexpected error: %v", err)
+	}
+	for i, down := range frames {
+		if !km.UpdateFrom(rp) {
+			t.Fatalf("replay ended early, at frame %d", i)
+		}
+		got := rp.Pointers()
+		if len(got) != len(down) {
+			t.Fatalf("frame %d: expected %d pointers, got %v", i, len(down), got)
+		}
+		for id, sp := range down {
+			if got[id] != sp {
+				t.Errorf("frame %d: pointer %d: expected %v, got %v", i, id, sp, got[id])
+			}
+		}
+	}
+}
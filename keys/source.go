@@ -0,0 +1,59 @@
+package keys
+
+import (
+	"github.com/hajimehoshi/ebiten"
+)
+
+// A Source provides the state of keys, one frame at a time.
+type Source interface {
+	// Next advances to the next frame, reporting false if there are no
+	// more frames.
+	Next() bool
+	// Down reports whether k is down in the current frame.
+	Down(k ebiten.Key) bool
+}
+
+type ebitenSource struct{}
+
+func (ebitenSource) Next() bool {
+	return true
+}
+
+func (ebitenSource) Down(k ebiten.Key) bool {
+	return ebiten.IsKeyPressed(k)
+}
+
+// Ebiten is the Source for the live keyboard.
+var Ebiten Source = ebitenSource{}
+
+// Script is a Source which yields a fixed sequence of frames, each
+// listing the keys down during that frame.
+type Script struct {
+	Frames [][]ebiten.Key
+	frame  int // number of frames advanced through
+}
+
+// NewScript creates a Script from the given frames.
+func NewScript(frames ...[]ebiten.Key) *Script {
+	return &Script{Frames: frames}
+}
+
+func (s *Script) Next() bool {
+	if s.frame >= len(s.Frames) {
+		return false
+	}
+	s.frame++
+	return true
+}
+
+func (s *Script) Down(k ebiten.Key) bool {
+	if s.frame == 0 {
+		return false
+	}
+	for _, d := range s.Frames[s.frame-1] {
+		if d == k {
+			return true
+		}
+	}
+	return false
+}
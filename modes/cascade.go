@@ -0,0 +1,254 @@
+package modes
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// cascadeDir is the direction the active row of a cascade moves in.
+// The directions are in clockwise order, so rotating is just adding
+// or subtracting one.
+type cascadeDir int
+
+const (
+	cascadeDown cascadeDir = iota
+	cascadeLeft
+	cascadeUp
+	cascadeRight
+)
+
+var cascadeSteps = [4]g.IVec{
+	{X: 0, Y: 1},
+	{X: -1, Y: 0},
+	{X: 0, Y: -1},
+	{X: 1, Y: 0},
+}
+
+// step is the motion from one active row to the next.
+func (d cascadeDir) step() g.IVec {
+	return cascadeSteps[d&3]
+}
+
+// before is the offset, within a row, of the square which feeds into a
+// square along with the square directly behind it. It's the step vector
+// rotated clockwise, which is the next direction's step.
+func (d cascadeDir) before() g.IVec {
+	return cascadeSteps[(d+1)&3]
+}
+
+// along is the unit vector along a row for this direction.
+func (d cascadeDir) along() g.IVec {
+	if d.step().X == 0 {
+		return g.IVec{X: 1, Y: 0}
+	}
+	return g.IVec{X: 0, Y: 1}
+}
+
+// rotate yields the direction n quarter-turns clockwise from d.
+func (d cascadeDir) rotate(n int) cascadeDir {
+	return cascadeDir(((int(d)+n)%4 + 4) % 4)
+}
+
+// cascadeMode is one of the internal modes based on cellular automata
+type cascadeMode struct {
+	cycleTime       int // number of ticks to go by between updates
+	colorMultiplier int // interpolation between palette colors
+	dir             cascadeDir
+}
+
+const (
+	cascadeCycleTime       = 2
+	cascadeColorMultiplier = 6
+	// number of columns to fade each update
+	cascadeFadeRate = 6
+	// starting alpha for squares
+	cascadeFaded = 0.75
+)
+
+var cascadeModes = []cascadeMode{
+	{cycleTime: cascadeCycleTime, colorMultiplier: cascadeColorMultiplier, dir: cascadeDown},
+}
+
+func init() {
+	for _, mode := range cascadeModes {
+		defaultList.Add(mode)
+	}
+}
+
+func (m cascadeMode) Name() string {
+	return "cascade"
+}
+
+func (m cascadeMode) Description() string {
+	return "cellular automaton cascading across the screen"
+}
+
+func (m cascadeMode) Settings() []Setting {
+	return []Setting{
+		cycleTimeSetting(m.cycleTime),
+		colorMultiplierSetting(m.colorMultiplier),
+	}
+}
+
+func (m cascadeMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	m.colorMultiplier = v.Int("colorMultiplier")
+	return m, nil
+}
+
+func (m cascadeMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
+	return newCascadeScene(m, gctx, detail, p)
+}
+
+type cascadeScene struct {
+	palette    *g.Palette
+	gctx       *g.Context
+	mode       cascadeMode
+	detail     int
+	gr         *g.SquareGrid
+	cycle      int
+	dir        cascadeDir
+	active     g.ILoc // a square on the active row
+	compute    [][]byte
+	colors     [2]g.Paint
+	fadeColumn int
+}
+
+func newCascadeScene(m cascadeMode, gctx *g.Context, detail int, p *g.Palette) (*cascadeScene, error) {
+	sc := &cascadeScene{mode: m, gctx: gctx, detail: detail, dir: m.dir}
+	err := sc.Reset(detail, p)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (s *cascadeScene) Mode() Mode {
+	return s.mode
+}
+
+func (s *cascadeScene) Reset(detail int, p *g.Palette) error {
+	_ = s.Hide()
+	s.detail = detail
+	s.palette = p.Interpolate(s.mode.colorMultiplier)
+	err := s.Display()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *cascadeScene) Display() error {
+	s.gr = s.gctx.NewSquareGrid(s.detail, 1, s.palette)
+	s.compute = make([][]byte, s.gr.Width)
+	for i := range s.compute {
+		s.compute[i] = make([]byte, s.gr.Height)
+	}
+	s.colors = [2]g.Paint{0, s.palette.Paint(s.mode.colorMultiplier)}
+	s.gr.Iterate(func(generic g.Grid, l g.ILoc, n int, c *g.Cell) {
+		c.P = s.colors[0]
+		c.Alpha = cascadeFaded
+		if l.Y == 0 {
+			c.Alpha += 0.1
+		}
+	})
+	s.active = g.ILoc{}
+	s.fadeColumn = 0
+	s.gr.Cells[0][0].P = s.colors[1]
+	s.compute[0][0] = 1
+	return nil
+}
+
+func (s *cascadeScene) Hide() error {
+	s.gr = nil
+	s.compute = nil
+	return nil
+}
+
+func (s *cascadeScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	if km.Pressed(ebiten.KeyLeft) {
+		s.dir = s.dir.rotate(-1)
+	}
+	if km.Pressed(ebiten.KeyDown) {
+		s.dir = s.dir.rotate(1)
+	}
+	s.cycle = (s.cycle + 1) % s.mode.cycleTime
+	if s.cycle != 0 {
+		return false, nil
+	}
+	step, before, along := s.dir.step(), s.dir.before(), s.dir.along()
+	rowLen, rows := s.gr.Width, s.gr.Height
+	if step.X != 0 {
+		rowLen, rows = s.gr.Height, s.gr.Width
+	}
+	// when the cascade wraps around, it shifts sideways a square, so
+	// the pattern doesn't just repeat.
+	prev := s.active
+	next, stepBack := s.gr.Add(prev, step)
+	s.active = next
+	fadeRatio := float32(cascadeFadeRate*s.gr.Width) / float32(rows) / 2
+
+	var previousState byte
+	toggles := 0
+	allFalse := true
+	for i := 0; i < rowLen; i++ {
+		above, _ := s.gr.Add(prev, along.Times(i))
+		beforeLoc, _ := s.gr.Add(above, before)
+		l, _ := s.gr.Add(next, along.Times(i))
+		if stepBack {
+			l, _ = s.gr.Add(l, before)
+		}
+		state := s.compute[above.X][above.Y] + s.compute[beforeLoc.X][beforeLoc.Y]
+		state %= 2
+		s.compute[l.X][l.Y] = state
+		if state != previousState {
+			toggles++
+			previousState = state
+		}
+		c := s.gr.At(l)
+		if state == 1 {
+			allFalse = false
+			c.Alpha = 1
+		} else {
+			c.IncAlpha(0.0065 * fadeRatio)
+		}
+		c.P = s.colors[state]
+	}
+	// turn one light on randomly if the row went dark
+	if allFalse {
+		l, _ := s.gr.Add(next, along.Times(int(s.gctx.Rand().Int31n(int32(rowLen)))))
+		s.compute[l.X][l.Y] = 1
+		c := s.gr.At(l)
+		c.Alpha = 1
+		c.P = s.colors[1]
+	}
+	mult := s.mode.colorMultiplier
+	s.colors[0] = s.palette.Inc(s.colors[0], 1)
+	s.colors[1] = s.palette.Inc(s.colors[1], 1)
+	if idx := int(s.colors[0]) % mult; idx == 0 || idx == mult/2 {
+		voice.Play(2*int(s.colors[0])/mult, 80)
+	}
+	for i := 0; i < cascadeFadeRate; i++ {
+		for j := range s.gr.Cells[s.fadeColumn] {
+			c := &s.gr.Cells[s.fadeColumn][j]
+			c.Alpha -= 0.006
+			if c.Alpha < 0.005 {
+				c.Alpha = 0.005
+			}
+		}
+		s.fadeColumn = (s.fadeColumn + 1) % s.gr.Width
+	}
+	if toggles > 0 {
+		voice.Play(toggles, 50)
+	}
+	return true, nil
+}
+
+func (s *cascadeScene) Draw(screen *ebiten.Image) error {
+	s.gctx.Render(screen, func(t *ebiten.Image, scale float32) {
+		s.gr.Draw(t, scale)
+	})
+	return nil
+}
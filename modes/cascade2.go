@@ -0,0 +1,263 @@
+package modes
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// cascade2Mode is a cellular automaton where the screen scrolls upwards,
+// and new rows are computed at the bottom. Changes made to a row
+// propagate downwards one row per update.
+type cascade2Mode struct {
+	cycleTime       int // number of ticks to go by between updates
+	colorMultiplier int // interpolation between palette colors
+}
+
+const cascade2CycleTime = 2
+
+var cascade2Modes = []cascade2Mode{
+	{cycleTime: cascade2CycleTime, colorMultiplier: cascadeColorMultiplier},
+}
+
+func init() {
+	for _, mode := range cascade2Modes {
+		defaultList.Add(mode)
+	}
+}
+
+func (m cascade2Mode) Name() string {
+	return "cascade2"
+}
+
+func (m cascade2Mode) Description() string {
+	return "cellular automaton with more interesting change propagation"
+}
+
+func (m cascade2Mode) Settings() []Setting {
+	return []Setting{
+		cycleTimeSetting(m.cycleTime),
+		colorMultiplierSetting(m.colorMultiplier),
+	}
+}
+
+func (m cascade2Mode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	m.colorMultiplier = v.Int("colorMultiplier")
+	return m, nil
+}
+
+func (m cascade2Mode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
+	return newCascade2Scene(m, gctx, detail, p)
+}
+
+// cascade2Row is the per-row state. A positive flag means the row has
+// changed, and the change should propagate to the next row down. A
+// negative flag means the row just received a change, which it will
+// pass on in the next update.
+type cascade2Row struct {
+	flag   int
+	colors [2]g.Paint
+}
+
+type cascade2Scene struct {
+	palette *g.Palette
+	gctx    *g.Context
+	mode    cascade2Mode
+	detail  int
+	gr      *g.SquareGrid
+	cycle   int
+	toggle  bool
+	compute [][]byte
+	rows    []cascade2Row
+	colors  [2]g.Paint
+}
+
+func newCascade2Scene(m cascade2Mode, gctx *g.Context, detail int, p *g.Palette) (*cascade2Scene, error) {
+	sc := &cascade2Scene{mode: m, gctx: gctx, detail: detail}
+	err := sc.Reset(detail, p)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (s *cascade2Scene) Mode() Mode {
+	return s.mode
+}
+
+func (s *cascade2Scene) Reset(detail int, p *g.Palette) error {
+	_ = s.Hide()
+	s.detail = detail
+	s.palette = p.Interpolate(s.mode.colorMultiplier)
+	err := s.Display()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *cascade2Scene) Display() error {
+	s.gr = s.gctx.NewSquareGrid(s.detail, 1, s.palette)
+	s.compute = make([][]byte, s.gr.Width)
+	for i := range s.compute {
+		s.compute[i] = make([]byte, s.gr.Height)
+	}
+	h := s.gr.Height
+	s.colors = [2]g.Paint{s.palette.Paint(-h), s.palette.Paint(s.mode.colorMultiplier - h)}
+	s.rows = make([]cascade2Row, h)
+	for y := range s.rows {
+		s.rows[y].colors = s.colors
+		s.colors[0] = s.palette.Inc(s.colors[0], 1)
+		s.colors[1] = s.palette.Inc(s.colors[1], 1)
+	}
+	s.gr.Iterate(func(generic g.Grid, l g.ILoc, n int, c *g.Cell) {
+		c.P = s.rows[l.Y].colors[0]
+		c.Alpha = cascadeFaded + (1-cascadeFaded)*float32(l.Y+1)/float32(h)
+	})
+	s.gr.Cells[0][0].P = s.rows[0].colors[1]
+	s.compute[0][0] = 1
+	s.toggle = false
+	return nil
+}
+
+func (s *cascade2Scene) Hide() error {
+	s.gr = nil
+	s.compute = nil
+	s.rows = nil
+	return nil
+}
+
+// shift moves every row up one, moving the top row to the bottom.
+func (s *cascade2Scene) shift() {
+	for x, col := range s.gr.Cells {
+		top := col[0]
+		copy(col, col[1:])
+		col[len(col)-1] = top
+		compute := s.compute[x]
+		topCompute := compute[0]
+		copy(compute, compute[1:])
+		compute[len(compute)-1] = topCompute
+	}
+	top := s.rows[0]
+	copy(s.rows, s.rows[1:])
+	s.rows[len(s.rows)-1] = top
+}
+
+// touch toggles a square, and marks its row as changed so the change
+// will propagate down the screen.
+func (s *cascade2Scene) touch(l g.ILoc) {
+	c := s.gr.At(l)
+	s.rows[l.Y].flag = 1
+	s.compute[l.X][l.Y] ^= 1
+	c.Alpha = 1
+	c.P = s.palette.Inc(c.P, s.mode.colorMultiplier)
+	s.gr.Neighbors(l, func(gr g.Grid, l g.ILoc, n int, c *g.Cell) {
+		c.IncAlpha(0.25)
+	})
+}
+
+// process computes a row from the row above it, returning the number
+// of times the state changed along the row.
+func (s *cascade2Scene) process(y int) (toggles int) {
+	var previousState byte
+	allFalse := true
+	row := &s.rows[y]
+	for x := range s.gr.Cells {
+		l := g.ILoc{X: x, Y: y}
+		after, _ := s.gr.Add(l, g.IVec{X: 0, Y: -1})
+		before, _ := s.gr.Add(after, g.IVec{X: -1, Y: 0})
+		state := (s.compute[before.X][before.Y] + s.compute[after.X][after.Y]) % 2
+		s.compute[x][y] = state
+		c := s.gr.At(l)
+		c.P = row.colors[state]
+		if state != previousState {
+			previousState = state
+			toggles++
+		}
+		if state == 1 {
+			allFalse = false
+			c.Alpha = 1
+		} else if y == s.gr.Height-1 && s.toggle {
+			// only brighten the new bottom row
+			c.IncAlpha(0.004 * float32(s.gr.Height))
+		}
+	}
+	if allFalse {
+		l := g.ILoc{X: s.gr.RandCol(), Y: y}
+		s.compute[l.X][l.Y] = 1
+		s.gr.Splash(l, 0, 1, func(gr g.Grid, l g.ILoc, n int, c *g.Cell) {
+			if n == 0 {
+				c.Alpha = 1
+				c.P = s.colors[1]
+			} else {
+				c.IncAlpha(0.1)
+			}
+		})
+	}
+	return toggles
+}
+
+func (s *cascade2Scene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	if km.Pressed(ebiten.KeyS) {
+		s.touch(s.gr.NewLoc())
+	}
+	s.cycle = (s.cycle + 1) % s.mode.cycleTime
+	if s.cycle != 0 {
+		return false, nil
+	}
+	s.toggle = !s.toggle
+	last := s.gr.Height - 1
+	if s.toggle {
+		s.rows[last].flag = 0
+		s.shift()
+		s.colors[0] = s.palette.Inc(s.colors[0], 1)
+		s.colors[1] = s.palette.Inc(s.colors[1], 1)
+		s.rows[last] = cascade2Row{colors: s.colors}
+	}
+	toggles := 0
+	for y := range s.rows {
+		row := &s.rows[y]
+		if s.toggle {
+			for x := range s.gr.Cells {
+				c := &s.gr.Cells[x][y]
+				c.Alpha -= 0.004
+				if c.Alpha < 0.005 {
+					c.Alpha = 0.005
+				}
+			}
+		}
+		if row.flag < 0 {
+			row.flag *= -1
+		}
+		process := y == last && s.toggle
+		if y > 0 && s.rows[y-1].flag > 0 {
+			process = true
+			if row.flag == 0 {
+				row.flag = -s.rows[y-1].flag
+				s.rows[y-1].flag = 0
+			}
+		}
+		if process {
+			toggles = s.process(y)
+		}
+	}
+	if s.toggle && toggles > 0 {
+		// pick a note by the row's hue, and go up an octave for
+		// busier rows
+		octave := 0
+		if toggles > s.gr.Width/2 {
+			octave = 1
+		}
+		voice.PlayOctave(int(s.colors[0])/s.mode.colorMultiplier, octave, 60)
+	}
+	return true, nil
+}
+
+func (s *cascade2Scene) Draw(screen *ebiten.Image) error {
+	s.gctx.Render(screen, func(t *ebiten.Image, scale float32) {
+		s.gr.Draw(t, scale)
+	})
+	return nil
+}
@@ -0,0 +1,207 @@
+package modes
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// compositeLayer is one of the layers of a composite mode, from the
+// bottom up.
+type compositeLayer struct {
+	mode        string // name of the mode to run in this layer
+	alpha       float32
+	composite   ebiten.CompositeMode // how the layer combines with the ones below it
+	tickDivider int                  // the layer ticks once every tickDivider ticks
+}
+
+// compositeMode is one of the internal modes which run several other
+// modes at once, drawing them on top of each other.
+type compositeMode struct {
+	name        string
+	description string
+	layers      []compositeLayer
+}
+
+var compositeModes = []compositeMode{
+	{
+		name:        "distanceKnights",
+		description: "three knights jumping over a dim distance grid",
+		layers: []compositeLayer{
+			{mode: "distance", alpha: 0.35, composite: ebiten.CompositeModeSourceOver, tickDivider: 2},
+			{mode: "knights3", alpha: 1, composite: ebiten.CompositeModeLighter, tickDivider: 1},
+		},
+	},
+}
+
+func init() {
+	for _, mode := range compositeModes {
+		defaultList.Add(mode)
+	}
+}
+
+func (m compositeMode) Name() string {
+	return m.name
+}
+
+func (m compositeMode) Description() string {
+	return m.description
+}
+
+func (m compositeMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
+	return newCompositeScene(m, gctx, detail, p)
+}
+
+// compositeScene runs a scene for each layer, drawing each to an
+// offscreen image, then drawing those to the screen. Only the top layer
+// gets input, or sound.
+type compositeScene struct {
+	gctx   *g.Context
+	mode   compositeMode
+	cycle  int
+	scenes []Scene
+	images []*ebiten.Image
+}
+
+func newCompositeScene(m compositeMode, gctx *g.Context, detail int, p *g.Palette) (*compositeScene, error) {
+	sc := &compositeScene{mode: m, gctx: gctx}
+	err := sc.newScenes(detail, p)
+	if err == nil {
+		err = sc.newImages()
+	}
+	if err != nil {
+		// release whatever the layers which did start up allocated
+		_ = sc.Hide()
+		return nil, err
+	}
+	return sc, nil
+}
+
+// newScenes creates a scene for each layer, stopping at the first error.
+func (s *compositeScene) newScenes(detail int, p *g.Palette) error {
+	for _, layer := range s.mode.layers {
+		if layer.tickDivider < 1 {
+			return fmt.Errorf("layer %s: tick divider must be at least 1", layer.mode)
+		}
+		// modes are looked up here, rather than when the composite mode
+		// is registered, so layers pick up any configured settings.
+		mode := defaultList.Find(layer.mode)
+		if mode == nil {
+			return fmt.Errorf("layer %s: unknown mode", layer.mode)
+		}
+		scene, err := mode.New(s.gctx, detail, p)
+		if err != nil {
+			return fmt.Errorf("layer %s: %v", layer.mode, err)
+		}
+		s.scenes = append(s.scenes, scene)
+	}
+	return nil
+}
+
+func (s *compositeScene) Mode() Mode {
+	return s.mode
+}
+
+func (s *compositeScene) newImages() error {
+	s.images = make([]*ebiten.Image, len(s.scenes))
+	for i := range s.images {
+		var err error
+		s.images[i], err = s.gctx.NewScreenImage()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *compositeScene) Reset(detail int, p *g.Palette) error {
+	for i, scene := range s.scenes {
+		err := scene.Reset(detail, p)
+		if err != nil {
+			return fmt.Errorf("layer %s: %v", s.mode.layers[i].mode, err)
+		}
+	}
+	// resetting a hidden scene displays it again, so it needs its
+	// images back
+	if s.images != nil {
+		return nil
+	}
+	return s.newImages()
+}
+
+func (s *compositeScene) Display() error {
+	for i, scene := range s.scenes {
+		err := scene.Display()
+		if err != nil {
+			return fmt.Errorf("layer %s: %v", s.mode.layers[i].mode, err)
+		}
+	}
+	if s.images != nil {
+		return nil
+	}
+	return s.newImages()
+}
+
+func (s *compositeScene) Hide() error {
+	for _, img := range s.images {
+		if img != nil {
+			img.Dispose()
+		}
+	}
+	s.images = nil
+	for i, scene := range s.scenes {
+		err := scene.Hide()
+		if err != nil {
+			return fmt.Errorf("layer %s: %v", s.mode.layers[i].mode, err)
+		}
+	}
+	return nil
+}
+
+func (s *compositeScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	stepped := false
+	top := len(s.scenes) - 1
+	for i, scene := range s.scenes {
+		if s.cycle%s.mode.layers[i].tickDivider != 0 {
+			continue
+		}
+		var err error
+		if i == top {
+			stepped, err = scene.Tick(voice, km, pointers)
+		} else {
+			_, err = scene.Tick(nil, nil, nil)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	s.cycle++
+	return stepped, nil
+}
+
+func (s *compositeScene) Draw(screen *ebiten.Image) error {
+	op := &ebiten.DrawImageOptions{}
+	for i, scene := range s.scenes {
+		layer := s.mode.layers[i]
+		img := s.images[i]
+		err := img.Clear()
+		if err != nil {
+			return err
+		}
+		err = scene.Draw(img)
+		if err != nil {
+			return err
+		}
+		op.ColorM.Reset()
+		op.ColorM.Scale(1, 1, 1, float64(layer.alpha))
+		op.CompositeMode = layer.composite
+		err = screen.DrawImage(img, op)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
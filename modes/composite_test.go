@@ -0,0 +1,115 @@
+package modes
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// newTestComposite creates a composite scene from recording scenes, with
+// the given tick divider for each layer.
+func newTestComposite(t *testing.T, dividers ...int) (*compositeScene, []*recordingScene) {
+	c := g.NewContext(1280, 960, false)
+	sc := &compositeScene{gctx: c}
+	var layers []*recordingScene
+	for _, d := range dividers {
+		rs := &recordingScene{displayed: true}
+		layers = append(layers, rs)
+		sc.mode.layers = append(sc.mode.layers, compositeLayer{alpha: 1, tickDivider: d})
+		sc.scenes = append(sc.scenes, rs)
+	}
+	if err := sc.newImages(); err != nil {
+		t.Fatalf("creating images: %v", err)
+	}
+	return sc, layers
+}
+
+func TestCompositeTick(t *testing.T) {
+	sc, layers := newTestComposite(t, 2, 3, 1)
+	voice := &sound.Voice{}
+	km := keys.NewMap(ebiten.KeyA)
+	pointers := keys.Pointers{{ID: keys.MouseID, State: keys.PRESS}}
+	for i := 0; i < 12; i++ {
+		if _, err := sc.Tick(voice, km, pointers); err != nil {
+			t.Fatalf("tick %d: %v", i, err)
+		}
+	}
+	for i, expected := range []int{6, 4, 12} {
+		if layers[i].ticks != expected {
+			t.Errorf("layer %d: expected %d ticks, got %d", i, expected, layers[i].ticks)
+		}
+	}
+	for i, l := range layers[:2] {
+		if l.withInput != 0 || l.withVoice != 0 {
+			t.Errorf("layer %d: expected no input or voice, got %d and %d ticks with them", i, l.withInput, l.withVoice)
+		}
+	}
+	if top := layers[2]; top.withInput != top.ticks || top.withVoice != top.ticks {
+		t.Errorf("top layer: expected input and voice on all %d ticks, got %d and %d", top.ticks, top.withInput, top.withVoice)
+	}
+}
+
+func TestCompositeHideDisplay(t *testing.T) {
+	sc, layers := newTestComposite(t, 1, 1)
+	if err := sc.Hide(); err != nil {
+		t.Fatalf("hiding: %v", err)
+	}
+	if sc.images != nil {
+		t.Errorf("expected Hide to release images")
+	}
+	for i, l := range layers {
+		if l.displayed {
+			t.Errorf("layer %d: still displayed after Hide", i)
+		}
+	}
+	if err := sc.Display(); err != nil {
+		t.Fatalf("displaying: %v", err)
+	}
+	if len(sc.images) != len(layers) {
+		t.Fatalf("expected Display to create %d images, got %d", len(layers), len(sc.images))
+	}
+	for i, img := range sc.images {
+		if img == nil {
+			t.Errorf("image %d: not created by Display", i)
+		}
+	}
+	for i, l := range layers {
+		if !l.displayed {
+			t.Errorf("layer %d: not displayed after Display", i)
+		}
+	}
+	screen, err := sc.gctx.NewScreenImage()
+	if err != nil {
+		t.Fatalf("creating screen: %v", err)
+	}
+	defer screen.Dispose()
+	if err := sc.Draw(screen); err != nil {
+		t.Errorf("drawing after Display: %v", err)
+	}
+}
+
+func TestCompositeResetHidden(t *testing.T) {
+	sc, layers := newTestComposite(t, 1, 1)
+	if err := sc.Hide(); err != nil {
+		t.Fatalf("hiding: %v", err)
+	}
+	if err := sc.Reset(10, g.Palettes["rainbow"]); err != nil {
+		t.Fatalf("resetting: %v", err)
+	}
+	for i, l := range layers {
+		if !l.displayed {
+			t.Errorf("layer %d: not displayed after Reset", i)
+		}
+	}
+	screen, err := sc.gctx.NewScreenImage()
+	if err != nil {
+		t.Fatalf("creating screen: %v", err)
+	}
+	defer screen.Dispose()
+	if err := sc.Draw(screen); err != nil {
+		t.Errorf("drawing after Reset: %v", err)
+	}
+}
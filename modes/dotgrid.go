@@ -364,6 +364,19 @@ func (m dotGridMode) Description() string {
 	return "dots"
 }
 
+func (m dotGridMode) Settings() []Setting {
+	return []Setting{
+		cycleTimeSetting(m.cycleTime),
+		intSetting("depth", "number of dots per grid square", m.depth, 1, 32),
+	}
+}
+
+func (m dotGridMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	m.depth = v.Int("depth")
+	return m, nil
+}
+
 func (m dotGridMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
 	return newDotGridScene(m, gctx, detail, p)
 }
@@ -398,6 +411,7 @@ func (s *dotGridScene) Mode() Mode {
 
 func (s *dotGridScene) Reset(detail int, p *g.Palette) error {
 	_ = s.Hide()
+	s.detail = detail
 	s.palette = p.Interpolate(12)
 	err := s.Display()
 	if err != nil {
@@ -426,7 +440,7 @@ func (s *dotGridScene) Hide() error {
 	return nil
 }
 
-func (s *dotGridScene) Tick(voice *sound.Voice, km keys.Map) (bool, error) {
+func (s *dotGridScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
 	s.cycle = (s.cycle + 1) % s.mode.cycleTime
 	if s.cycle != 0 {
 		return false, nil
@@ -0,0 +1,485 @@
+package modes
+
+import (
+	math "github.com/chewxy/math32"
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// fireParams are the tunables for a fire field. Energy is measured in
+// palette colors, so an energy of 1 is colorMultiplier palette entries.
+type fireParams struct {
+	colorMultiplier int
+	faded           float32 // starting alpha
+	idleTime        int     // updates between idle sparks
+	idleEnergy      float32 // base energy of idle sparks
+	touchEnergy     float32 // energy of a touch
+	idleFloor       float32 // alpha that quiet squares fade back to
+	rangeScale      float32 // how far energy spreads, for a 768-square grid
+	decay           float32 // energy lost per update, beyond the 10% falloff
+}
+
+var defaultFireParams = fireParams{
+	colorMultiplier: 6,
+	faded:           0.1,
+	idleTime:        7,
+	idleEnergy:      1,
+	touchEnergy:     6.5,
+	idleFloor:       0.2,
+	rangeScale:      1.8,
+	decay:           0.1,
+}
+
+func (p fireParams) settings() []Setting {
+	return []Setting{
+		colorMultiplierSetting(p.colorMultiplier),
+		floatSetting("faded", "starting alpha", p.faded, 0, 1),
+		intSetting("idleTime", "updates between idle sparks", p.idleTime, 1, 600),
+		floatSetting("idleEnergy", "base energy of idle sparks", p.idleEnergy, 0, 16),
+		floatSetting("touchEnergy", "energy of a touch", p.touchEnergy, 0, 16),
+		floatSetting("idleFloor", "alpha that quiet squares fade back to", p.idleFloor, 0, 1),
+		floatSetting("rangeScale", "how far energy spreads, for a 768-square grid", p.rangeScale, 0, 16),
+		floatSetting("decay", "energy lost per update, beyond the 10% falloff", p.decay, 0, 1),
+	}
+}
+
+func (p *fireParams) configure(v Values) {
+	p.colorMultiplier = v.Int("colorMultiplier")
+	p.faded = v.Float("faded")
+	p.idleTime = v.Int("idleTime")
+	p.idleEnergy = v.Float("idleEnergy")
+	p.touchEnergy = v.Float("touchEnergy")
+	p.idleFloor = v.Float("idleFloor")
+	p.rangeScale = v.Float("rangeScale")
+	p.decay = v.Float("decay")
+}
+
+var firePlus = []g.IVec{
+	{X: -1, Y: 0},
+	{X: 1, Y: 0},
+	{X: 0, Y: -1},
+	{X: 0, Y: 1},
+}
+
+var fireDiag = []g.IVec{
+	{X: -1, Y: -1},
+	{X: -1, Y: 1},
+	{X: 1, Y: -1},
+	{X: 1, Y: 1},
+}
+
+// fireSquare is the per-square energy state of a fire field. Hues are
+// in palette entries, offset by one color, so a hue of colorMultiplier
+// is the first color of the palette.
+type fireSquare struct {
+	energy      float32
+	vested      float32 // energy which has been spread to other squares
+	spreadSoFar float32
+	energyHue   float32 // the hue this square is moving towards
+	hue         float32
+	fadeFloor   float32
+	lit         bool
+	spreading   bool
+	force       bool
+	blocked     []g.ILoc // squares which won't be energized by this square
+}
+
+// fireSource describes where energy came from. A nil *fireSource is an
+// outside event.
+type fireSource struct {
+	l      g.ILoc
+	square bool // the source is a square, which shouldn't get energy back
+	force  bool // energy from this source doesn't downgrade squares
+}
+
+// fireField is a field of energy running in parallel with a square grid,
+// which it maps onto the grid's cells' paint, alpha, and scale.
+type fireField struct {
+	fireParams
+	gr             *g.SquareGrid
+	palette        *g.Palette
+	squares        [][]fireSquare
+	cm             float32
+	idleThreshold  float32
+	cmCap          float32
+	rangeScale     float32
+	fadeMultiplier float32
+	events         int
+	cooldown       int
+}
+
+func newFireField(gr *g.SquareGrid, params fireParams) *fireField {
+	f := &fireField{fireParams: params, gr: gr, palette: gr.Palette()}
+	f.cm = float32(params.colorMultiplier)
+	f.idleThreshold = (params.idleEnergy + 1) * f.cm
+	// 6.8 ~= 0.8 => nearly-red
+	f.cmCap = f.cm * 6.8
+	f.rangeScale = params.rangeScale * float32(gr.Width*gr.Height) / 768
+	f.fadeMultiplier = 0.005
+	f.squares = make([][]fireSquare, gr.Width)
+	for x := range f.squares {
+		f.squares[x] = make([]fireSquare, gr.Height)
+		for y := range f.squares[x] {
+			f.squares[x][y] = fireSquare{fadeFloor: params.idleFloor, energyHue: f.cm, hue: f.cm}
+		}
+	}
+	gr.Iterate(func(generic g.Grid, l g.ILoc, n int, c *g.Cell) {
+		c.Alpha = params.faded
+		c.Scale = 0.7 + 1.0/18
+		f.colorize(l)
+	})
+	return f
+}
+
+// scaleAdd yields the larger of a and b, plus a fraction of the smaller.
+func scaleAdd(a, b, divisor float32) float32 {
+	if a > b {
+		a, b = b, a
+	}
+	scale := 1 / divisor
+	if a >= 1 {
+		scale *= (a * a) / (b * b)
+	} else {
+		scale *= a / b
+	}
+	return b + (a * scale)
+}
+
+func (f *fireField) colorize(l g.ILoc) {
+	f.gr.Cells[l.X][l.Y].P = f.palette.Paint(int(f.squares[l.X][l.Y].hue) - f.colorMultiplier)
+}
+
+func (f *fireField) isBlocked(sq *fireSquare, src g.ILoc) bool {
+	for _, b := range sq.blocked {
+		if b == src {
+			return true
+		}
+	}
+	return false
+}
+
+// spreadPlus spreads energy from l to the squares rng squares away from
+// it, and for even ranges, also diagonally.
+func (f *fireField) spreadPlus(l g.ILoc, rng int) {
+	if rng < 1 {
+		return
+	}
+	sq := &f.squares[l.X][l.Y]
+	mod := (sq.energy - float32(rng) + 1) / (2 * f.cm)
+	if mod > 5 {
+		mod = 5
+	}
+	hue := sq.energyHue - float32(rng)*f.cm/f.rangeScale
+	effective := sq.energyHue / f.cm
+	if effective > 4 {
+		effective = 4
+	}
+	alpha := (f.gr.Cells[l.X][l.Y].Alpha * effective / 4) - (0.1 * float32(rng))
+	src := &fireSource{l: l, square: true, force: sq.force}
+	for _, v := range firePlus {
+		there, wrapped := f.gr.Add(l, v.Times(rng))
+		if !wrapped && !f.isBlocked(&f.squares[there.X][there.Y], l) {
+			f.energize(there, mod, hue, alpha, src)
+		}
+	}
+	if rng%2 == 0 {
+		rng /= 2
+		mod /= 2
+		alpha /= 2
+		hue -= f.cm / 2
+		for _, v := range fireDiag {
+			there, wrapped := f.gr.Add(l, v.Times(rng))
+			if !wrapped && !f.isBlocked(&f.squares[there.X][there.Y], l) {
+				f.energize(there, mod, hue, alpha, src)
+			}
+		}
+	}
+}
+
+// energize adds energy to a square, returning whether the square is now
+// spreading energy to its neighbors.
+func (f *fireField) energize(l g.ILoc, amount, hue, alpha float32, src *fireSource) bool {
+	sq := &f.squares[l.X][l.Y]
+	c := &f.gr.Cells[l.X][l.Y]
+	amount *= f.cm
+	if alpha < f.idleFloor {
+		alpha = f.idleFloor
+	}
+	if amount < 0.1 {
+		return false
+	}
+	// limit amounts added
+	if amount > f.cmCap {
+		amount = f.cmCap
+	}
+	hue = scaleAdd(hue, sq.energyHue, 8)
+	if hue < f.cm {
+		// allow hue to go slightly purpler than red.
+		hue = f.cm - 1
+	} else if hue > f.cmCap {
+		hue = f.cmCap
+	}
+	// so, do we forcibly update?
+	forceValue := true
+	if src != nil {
+		if !src.force {
+			if sq.lit && sq.energy > amount {
+				forceValue = false
+			}
+		} else {
+			// don't downgrade energy
+			floor := f.cm
+			if sq.lit {
+				floor = sq.energy
+			}
+			hue = math.Max(floor, math.Max(hue, sq.energyHue))
+			amount = math.Max(amount, sq.energy)
+		}
+	} else {
+		if sq.lit && sq.energy*5 > amount {
+			forceValue = false
+		}
+	}
+	if forceValue {
+		sq.energy = amount
+		sq.spreadSoFar = 0
+		sq.energyHue = hue
+		c.Alpha = math.Min(scaleAdd(c.Alpha, alpha, 1), 1)
+		if sq.energy > f.idleThreshold+(f.cm*2) && hue > (f.cm*4) {
+			sq.spreading = true
+		}
+	} else {
+		oldEnergy := sq.energy
+		sq.energy = scaleAdd(sq.energy, amount, 16)
+		c.Alpha = math.Max(c.Alpha, math.Min(alpha, c.Alpha+amount))
+		sq.energyHue = hue
+		if sq.energy > f.idleThreshold && oldEnergy < f.idleThreshold {
+			sq.spreading = true
+			sq.spreadSoFar = 0
+		}
+	}
+	sq.lit = true
+	if src != nil && src.square {
+		sq.blocked = append(sq.blocked, src.l)
+	}
+	// rarely-hit spots energize a little further
+	sq.energy += sq.fadeFloor * f.cm
+	sq.fadeFloor = math.Max(0.001, sq.fadeFloor-amount/f.cm/2)
+	return sq.spreading
+}
+
+// touch adds a burst of energy at l.
+func (f *fireField) touch(voice *sound.Voice, l g.ILoc, energy float32) {
+	sq := &f.squares[l.X][l.Y]
+	note := int(sq.energyHue/f.cm + 0.5)
+	if note > 6 {
+		note = 6
+	}
+	voice.PlayOctave(note, 1, 75)
+	f.energize(l, energy, energy*f.cm, 1, nil)
+	f.gr.Cells[l.X][l.Y].Alpha = 1
+	sq.spreading = true
+	sq.force = true
+}
+
+// tick updates every square in the field, spreading and fading energy.
+// It returns true once every idleTime updates, when it's time for a new
+// spark.
+func (f *fireField) tick() bool {
+	rng := f.gr.Rand()
+	thisFrameFade := f.fadeMultiplier + (math.Sqrt(float32(f.events)) * .002)
+	total := int32(f.gr.Width * f.gr.Height)
+	f.events = 0
+	for x, col := range f.squares {
+		for y := range col {
+			l := g.ILoc{X: x, Y: y}
+			sq := &col[y]
+			c := &f.gr.Cells[x][y]
+			if sq.lit && sq.energy > 0.05 {
+				f.events++
+				if sq.vested < sq.energy {
+					oldRange := int(sq.spreadSoFar * f.rangeScale / f.cm)
+					scale := math.Min((sq.energy-sq.vested)/f.cm, sq.vested)
+					sq.vested = math.Min(sq.energy, sq.vested+scale+1)
+					if sq.spreading {
+						newRange := int(sq.vested * f.rangeScale / f.cm)
+						for j := oldRange + 1; j <= newRange; j++ {
+							f.spreadPlus(l, j)
+						}
+						sq.spreadSoFar = sq.vested
+					}
+				} else {
+					sq.force = false
+					sq.blocked = sq.blocked[:0]
+					sq.spreading = false
+					sq.energy = math.Max(sq.energy*0.90-f.decay, 0)
+					sq.vested = sq.energy
+					if sq.energyHue > f.cm {
+						sq.energyHue = math.Max(f.cm, sq.energyHue-(0.5*(sq.energyHue/f.cm)))
+					}
+				}
+				if sq.hue != sq.energyHue {
+					diff := sq.energyHue - sq.hue
+					if sq.hue < sq.energyHue {
+						sq.hue = math.Min(sq.energyHue, sq.hue+diff/3+2)
+					} else {
+						sq.hue = math.Max(f.cm, sq.hue+diff/4)
+					}
+					f.colorize(l)
+				}
+			} else {
+				sq.blocked = sq.blocked[:0]
+				sq.lit = false
+				sq.energy = 0
+				sq.vested = 0
+				if sq.fadeFloor < f.idleFloor {
+					sq.fadeFloor = math.Min(sq.fadeFloor+0.001, f.idleFloor)
+				}
+				fade := thisFrameFade
+				if sq.hue > f.cm {
+					sq.hue = math.Max(sq.hue*0.99-1, f.cm)
+					f.colorize(l)
+					fade /= 4
+				}
+				c.Alpha = math.Max(sq.fadeFloor, c.Alpha-fade)
+			}
+			if rng.Int31n(total) == 0 {
+				sq.blocked = sq.blocked[:0]
+				if c.Alpha < 1 && sq.hue < f.cm*4 {
+					f.energize(l, (2/f.cm)+rng.Float32()+1, sq.hue+f.cm/2, .1, &fireSource{force: true})
+					// and make this one a little stickier
+					sq.energy += 3
+				}
+			}
+			c.Scale = 0.7 + (sq.energy / f.cm / 18)
+		}
+	}
+	f.cooldown--
+	if f.cooldown < 1 {
+		f.cooldown = f.idleTime
+		return true
+	}
+	return false
+}
+
+// spark starts a new fire at l.
+func (f *fireField) spark(l g.ILoc, energy float32) {
+	f.energize(l, energy/2, energy*f.cm/2, 1, nil)
+	sq := &f.squares[l.X][l.Y]
+	sq.spreading = true
+	sq.spreadSoFar = 0
+	sq.energy += 2
+}
+
+// fireMode is one of the internal modes based on energy spreading
+// through a grid
+type fireMode struct {
+	cycleTime int // number of ticks to go by between updates
+	params    fireParams
+}
+
+const fireCycleTime = 2
+
+var fireModes = []fireMode{
+	{cycleTime: fireCycleTime, params: defaultFireParams},
+}
+
+func init() {
+	for _, mode := range fireModes {
+		defaultList.Add(mode)
+	}
+}
+
+func (m fireMode) Name() string {
+	return "fire"
+}
+
+func (m fireMode) Description() string {
+	return "embers ignite and travel"
+}
+
+func (m fireMode) Settings() []Setting {
+	return append([]Setting{cycleTimeSetting(m.cycleTime)}, m.params.settings()...)
+}
+
+func (m fireMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	m.params.configure(v)
+	return m, nil
+}
+
+func (m fireMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
+	return newFireScene(m, gctx, detail, p)
+}
+
+type fireScene struct {
+	palette *g.Palette
+	gctx    *g.Context
+	mode    fireMode
+	detail  int
+	gr      *g.SquareGrid
+	fire    *fireField
+	cycle   int
+}
+
+func newFireScene(m fireMode, gctx *g.Context, detail int, p *g.Palette) (*fireScene, error) {
+	sc := &fireScene{mode: m, gctx: gctx, detail: detail}
+	err := sc.Reset(detail, p)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (s *fireScene) Mode() Mode {
+	return s.mode
+}
+
+func (s *fireScene) Reset(detail int, p *g.Palette) error {
+	_ = s.Hide()
+	s.detail = detail
+	s.palette = p.Interpolate(s.mode.params.colorMultiplier)
+	err := s.Display()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *fireScene) Display() error {
+	s.gr = s.gctx.NewSquareGrid(s.detail, 1, s.palette)
+	s.fire = newFireField(s.gr, s.mode.params)
+	return nil
+}
+
+func (s *fireScene) Hide() error {
+	s.gr = nil
+	s.fire = nil
+	return nil
+}
+
+func (s *fireScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	rng := s.gctx.Rand()
+	if km.Pressed(ebiten.KeyS) {
+		s.fire.touch(voice, s.gr.NewLoc(), s.mode.params.touchEnergy)
+	}
+	s.cycle = (s.cycle + 1) % s.mode.cycleTime
+	if s.cycle != 0 {
+		return false, nil
+	}
+	if s.fire.tick() {
+		energy := s.mode.params.idleEnergy + float32(rng.Int31n(4)+rng.Int31n(3))
+		voice.PlayOctave(int(energy), 0, 75)
+		s.fire.spark(s.gr.NewLoc(), energy)
+	}
+	return true, nil
+}
+
+func (s *fireScene) Draw(screen *ebiten.Image) error {
+	s.gctx.Render(screen, func(t *ebiten.Image, scale float32) {
+		s.gr.Draw(t, scale)
+	})
+	return nil
+}
@@ -0,0 +1,211 @@
+package modes
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// firebugsMode is one of the internal modes based on energy spreading
+// through a grid, with wandering bugs starting the fires.
+type firebugsMode struct {
+	cycleTime int // number of ticks to go by between updates
+	bugs      int
+	params    fireParams
+}
+
+const firebugsCycleTime = 2
+
+// firebugsParams yields the fire defaults, with energy decaying faster,
+// so each bug's fires stay small.
+func firebugsParams() fireParams {
+	p := defaultFireParams
+	p.decay = 0.2
+	return p
+}
+
+var firebugsModes = []firebugsMode{
+	{cycleTime: firebugsCycleTime, bugs: 6, params: firebugsParams()},
+}
+
+func init() {
+	for _, mode := range firebugsModes {
+		defaultList.Add(mode)
+	}
+}
+
+func (m firebugsMode) Name() string {
+	return "firebugs"
+}
+
+func (m firebugsMode) Description() string {
+	return "flaming things wander around igniting the world"
+}
+
+func (m firebugsMode) Settings() []Setting {
+	return append([]Setting{
+		cycleTimeSetting(m.cycleTime),
+		intSetting("bugs", "number of firebugs", m.bugs, 1, 64),
+	}, m.params.settings()...)
+}
+
+func (m firebugsMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	m.bugs = v.Int("bugs")
+	m.params.configure(v)
+	return m, nil
+}
+
+func (m firebugsMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
+	return newFirebugsScene(m, gctx, detail, p)
+}
+
+// firebug is a bug which wanders the grid. It moves smoothly from one
+// square to the next, rather than jumping.
+type firebug struct {
+	g.ILoc
+	from g.FLoc
+	hue  int // in palette colors
+	c    *g.FloatingCellBase
+}
+
+// apply positions the bug's cell t of the way from its previous square
+// to its current one.
+func (b *firebug) apply(t float32) {
+	if b.c != nil {
+		to := b.ILoc.FLoc()
+		*b.c.Loc() = g.FLoc{X: b.from.X + (to.X-b.from.X)*t, Y: b.from.Y + (to.Y-b.from.Y)*t}
+	}
+}
+
+type firebugsScene struct {
+	palette *g.Palette
+	gctx    *g.Context
+	mode    firebugsMode
+	detail  int
+	gr      *g.SquareGrid
+	fire    *fireField
+	bugs    []firebug
+	nextBug int
+	cycle   int
+}
+
+func newFirebugsScene(m firebugsMode, gctx *g.Context, detail int, p *g.Palette) (*firebugsScene, error) {
+	sc := &firebugsScene{mode: m, gctx: gctx, detail: detail, bugs: make([]firebug, m.bugs)}
+	err := sc.Reset(detail, p)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (s *firebugsScene) Mode() Mode {
+	return s.mode
+}
+
+func (s *firebugsScene) Reset(detail int, p *g.Palette) error {
+	_ = s.Hide()
+	s.detail = detail
+	s.palette = p.Interpolate(s.mode.params.colorMultiplier)
+	err := s.Display()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *firebugsScene) Display() error {
+	s.gr = s.gctx.NewSquareGrid(s.detail, 1, s.palette)
+	s.fire = newFireField(s.gr, s.mode.params)
+	for i := range s.bugs {
+		b := &s.bugs[i]
+		b.c = s.gr.NewExtraCell().(*g.FloatingCellBase)
+		b.ILoc = s.gr.NewLoc()
+		b.from = b.ILoc.FLoc()
+		b.hue = i + 1
+		b.c.Cell.P = s.palette.Paint(i * s.mode.params.colorMultiplier)
+		b.apply(1)
+	}
+	s.nextBug = 0
+	s.scaleBugs()
+	return nil
+}
+
+func (s *firebugsScene) Hide() error {
+	for i := range s.bugs {
+		s.bugs[i].c = nil
+	}
+	s.gr = nil
+	s.fire = nil
+	return nil
+}
+
+// scaleBugs makes each bug larger as its turn to move gets closer.
+func (s *firebugsScene) scaleBugs() {
+	idle := s.mode.params.idleTime
+	for i := range s.bugs {
+		near := float32(s.nextBug - i)
+		if near < 0 {
+			near += float32(len(s.bugs))
+		}
+		near += float32(idle-s.fire.cooldown) / float32(idle)
+		s.bugs[i].c.Cell.Scale = 0.4 + (near / float32(len(s.bugs)) * 0.7)
+	}
+}
+
+// move moves the next bug to the neighboring square which is least on
+// fire, and starts a fire there.
+func (s *firebugsScene) move(voice *sound.Voice) {
+	// every other bug has finished moving by now
+	for i := range s.bugs {
+		s.bugs[i].from = s.bugs[i].ILoc.FLoc()
+	}
+	b := &s.bugs[s.nextBug]
+	s.nextBug = (s.nextBug + 1) % len(s.bugs)
+	best := b.ILoc
+	found := false
+	for _, v := range firePlus {
+		l, wrapped := s.gr.Add(b.ILoc, v)
+		if wrapped {
+			continue
+		}
+		if !found || s.fire.squares[l.X][l.Y].energyHue < s.fire.squares[best.X][best.Y].energyHue {
+			best = l
+			found = true
+		}
+	}
+	b.ILoc = best
+	energy := s.mode.params.idleEnergy + float32(b.hue) + float32(s.gctx.Rand().Int31n(3)) - 1
+	voice.PlayOctave(int(s.fire.squares[best.X][best.Y].energyHue/s.fire.cm), 0, 75)
+	s.fire.spark(best, energy)
+}
+
+func (s *firebugsScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	if km.Pressed(ebiten.KeyS) {
+		s.fire.touch(voice, s.gr.NewLoc(), s.mode.params.touchEnergy)
+	}
+	s.cycle = (s.cycle + 1) % s.mode.cycleTime
+	if s.cycle != 0 {
+		return false, nil
+	}
+	if s.fire.tick() {
+		s.move(voice)
+	}
+	// the moving bug slides into its new square over the course of
+	// the idle time.
+	idle := float32(s.mode.params.idleTime)
+	t := (idle - float32(s.fire.cooldown) + 1) / idle
+	for i := range s.bugs {
+		s.bugs[i].apply(t)
+	}
+	s.scaleBugs()
+	return true, nil
+}
+
+func (s *firebugsScene) Draw(screen *ebiten.Image) error {
+	s.gctx.Render(screen, func(t *ebiten.Image, scale float32) {
+		s.gr.Draw(t, scale)
+	})
+	return nil
+}
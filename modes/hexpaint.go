@@ -32,6 +32,15 @@ func (m hexPaintMode) Description() string {
 	return "painting hexes"
 }
 
+func (m hexPaintMode) Settings() []Setting {
+	return []Setting{cycleTimeSetting(m.cycleTime)}
+}
+
+func (m hexPaintMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	return m, nil
+}
+
 func (m hexPaintMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
 	return newHexPaintScene(m, gctx, detail, p)
 }
@@ -51,7 +60,15 @@ func (p *hexPainter) apply() {
 	}
 }
 
+// hexSmear is a color picked up by a pointer, which it paints onto each
+// new hex it enters.
+type hexSmear struct {
+	g.ILoc
+	P g.Paint
+}
+
 type hexPaintScene struct {
+	smears      map[int]hexSmear // by pointer ID
 	nextPainter int
 	palette     *g.Palette
 	gctx        *g.Context
@@ -63,7 +80,7 @@ type hexPaintScene struct {
 }
 
 func newHexPaintScene(m hexPaintMode, gctx *g.Context, detail int, p *g.Palette) (*hexPaintScene, error) {
-	sc := &hexPaintScene{mode: m, gctx: gctx, detail: detail, palette: p, painters: make([]hexPainter, 6)}
+	sc := &hexPaintScene{mode: m, gctx: gctx, detail: detail, palette: p, painters: make([]hexPainter, 6), smears: make(map[int]hexSmear)}
 	err := sc.Reset(detail, p)
 	if err != nil {
 		return nil, err
@@ -77,6 +94,7 @@ func (s *hexPaintScene) Mode() Mode {
 
 func (s *hexPaintScene) Reset(detail int, p *g.Palette) error {
 	_ = s.Hide()
+	s.detail = detail
 	s.palette = p
 	err := s.Display()
 	if err != nil {
@@ -103,6 +121,7 @@ func (s *hexPaintScene) Display() error {
 		s.painters[i].dir = s.gr.NewDir()
 		s.painters[i].apply()
 	}
+	s.smears = make(map[int]hexSmear)
 	return nil
 }
 
@@ -114,10 +133,58 @@ func (s *hexPaintScene) Hide() error {
 	return nil
 }
 
-func (s *hexPaintScene) Tick(voice *sound.Voice, km keys.Map) (bool, error) {
+// smear lets pointers drag colors around. A pointer picks up the color
+// of the first hex it touches, and paints it onto each new hex it enters,
+// pulling the hexes around that towards it.
+func (s *hexPaintScene) smear(voice *sound.Voice, pointers keys.Pointers) bool {
+	for id := range s.smears {
+		found := false
+		for _, p := range pointers {
+			if p.ID == id && !p.Released() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			delete(s.smears, id)
+		}
+	}
+	smeared := false
+	for _, p := range pointers {
+		if !p.Down() {
+			continue
+		}
+		l, c := s.gr.CellAt(p.X, p.Y)
+		if c == nil {
+			continue
+		}
+		sm, ok := s.smears[p.ID]
+		if !ok {
+			s.smears[p.ID] = hexSmear{ILoc: l, P: c.P}
+			continue
+		}
+		if l == sm.ILoc {
+			continue
+		}
+		sm.ILoc = l
+		s.smears[p.ID] = sm
+		c.P = sm.P
+		c.Alpha = 1
+		s.gr.Splash(l, 1, 1, func(gr g.Grid, l g.ILoc, n int, c *g.Cell) {
+			c.P = s.palette.Toward(c.P, sm.P, 1)
+			c.IncAlpha(0.1)
+		})
+		voice.Play(int(sm.P), 60)
+		smeared = true
+	}
+	return smeared
+}
+
+func (s *hexPaintScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	smeared := s.smear(voice, pointers)
 	s.cycle = (s.cycle + 1) % s.mode.cycleTime
 	if s.cycle != 0 {
-		return false, nil
+		return smeared, nil
 	}
 	s.gr.Iterate(func(gr g.Grid, l g.ILoc, n int, c *g.Cell) {
 		c.IncAlpha(-0.001)
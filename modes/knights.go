@@ -21,17 +21,65 @@ var knightMoves = []g.IVec{
 	{X: -2, Y: 1},
 }
 
-func knightMove() g.IVec {
-	return knightMoves[int(rand.Int31n(int32(len(knightMoves))))]
+func knightMove(rng *rand.Rand) g.IVec {
+	return knightMoves[int(rng.Int31n(int32(len(knightMoves))))]
+}
+
+// squareDistance yields the squared distance between two locations on a
+// grid which wraps around.
+func squareDistance(gr *g.SquareGrid, a, b g.ILoc) int {
+	dx, dy := a.X-b.X, a.Y-b.Y
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	if gr.Width-dx < dx {
+		dx = gr.Width - dx
+	}
+	if gr.Height-dy < dy {
+		dy = gr.Height - dy
+	}
+	return dx*dx + dy*dy
+}
+
+// knightMoveToward yields the knight move from l which gets closest to
+// target, picking randomly among equally good moves.
+func knightMoveToward(rng *rand.Rand, gr *g.SquareGrid, l, target g.ILoc) g.IVec {
+	best, bestDist, ties := knightMoves[0], -1, 0
+	for _, m := range knightMoves {
+		next, _ := gr.Add(l, m)
+		d := squareDistance(gr, next, target)
+		switch {
+		case bestDist < 0 || d < bestDist:
+			best, bestDist, ties = m, d, 1
+		case d == bestDist:
+			ties++
+			if rng.Int31n(int32(ties)) == 0 {
+				best = m
+			}
+		}
+	}
+	return best
 }
 
 // knightMode is one of the internal modes based on knight moves
 type knightMode struct {
-	k         int // knights
-	cycleTime int // number of ticks to go by between updates
+	k         int  // knights
+	cycleTime int  // number of ticks to go by between updates
+	toward    bool // knights pull squares towards their own colors
 }
 
-const knightCycleTime = 10
+const (
+	knightCycleTime = 10
+	// resting alpha for squares in toward modes
+	knightFaded = 0.75
+	// number of columns to fade each update in toward modes
+	knightFadeRate = 3
+	// ticks a knight stays solid after landing, in toward modes
+	knightLandedTicks = 20
+)
 
 var knightModes = []knightMode{
 	{k: 1, cycleTime: knightCycleTime},
@@ -40,6 +88,7 @@ var knightModes = []knightMode{
 	{k: 4, cycleTime: knightCycleTime},
 	{k: 5, cycleTime: knightCycleTime},
 	{k: 6, cycleTime: knightCycleTime},
+	{k: 6, cycleTime: knightCycleTime, toward: true},
 }
 
 func init() {
@@ -49,21 +98,38 @@ func init() {
 }
 
 func (m knightMode) Name() string {
+	if m.toward {
+		return fmt.Sprintf("knightsToward%d", m.k)
+	}
 	return fmt.Sprintf("knights%d", m.k)
 }
 
 func (m knightMode) Description() string {
+	if m.toward {
+		return fmt.Sprintf("%d knights jumping, advancing colors towards their own", m.k)
+	}
 	return fmt.Sprintf("%d knights jumping", m.k)
 }
 
+func (m knightMode) Settings() []Setting {
+	return []Setting{cycleTimeSetting(m.cycleTime)}
+}
+
+func (m knightMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	return m, nil
+}
+
 func (m knightMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
 	return newKnightScene(m, gctx, detail, p)
 }
 
 type knight struct {
 	g.ILoc
-	P g.Paint
-	c *g.FloatingCellBase
+	P      g.Paint
+	target g.Paint // the color this knight pulls squares towards
+	landed int     // ticks since this knight last landed
+	c      *g.FloatingCellBase
 }
 
 func (k *knight) apply() {
@@ -74,15 +140,35 @@ func (k *knight) apply() {
 	}
 }
 
+// highlight makes the knight a solid marker for a while after it lands,
+// then a dim glow once it's been sitting on a square of its own color.
+func (k *knight) highlight(under g.Paint) {
+	if k.c == nil {
+		return
+	}
+	if k.landed >= knightLandedTicks && under == k.P {
+		k.c.Cell.Alpha = 0.6
+		k.c.BlendMode = g.BlendAdd
+	} else {
+		k.c.Cell.Alpha = 1
+		k.c.BlendMode = g.BlendNormal
+	}
+}
+
 type knightScene struct {
-	nextKnight int
-	palette    *g.Palette
-	gctx       *g.Context
-	mode       knightMode
-	knights    []knight
-	detail     int
-	gr         *g.SquareGrid
-	cycle      int
+	nextKnight     int
+	palette        *g.Palette
+	gctx           *g.Context
+	mode           knightMode
+	knights        []knight
+	detail         int
+	gr             *g.SquareGrid
+	cycle          int
+	fadeColumn     int
+	fadeMultiplier float32
+	toneBase       int
+	toneOffset     int
+	targets        []g.ILoc // squares under pointers, which knights move towards
 }
 
 func newKnightScene(m knightMode, gctx *g.Context, detail int, p *g.Palette) (*knightScene, error) {
@@ -100,6 +186,7 @@ func (s *knightScene) Mode() Mode {
 
 func (s *knightScene) Reset(detail int, p *g.Palette) error {
 	_ = s.Hide()
+	s.detail = detail
 	s.palette = p
 	err := s.Display()
 	if err != nil {
@@ -114,6 +201,10 @@ func (s *knightScene) Display() error {
 	p := s.gr.Palette().Paint(0)
 	s.gr.Iterate(func(generic g.Grid, l g.ILoc, n int, c *g.Cell) {
 		c.P = p
+		if s.mode.toward {
+			c.P = s.palette.Paint(l.X + l.Y)
+			c.Alpha = knightFaded
+		}
 	})
 	for i := 0; i < s.mode.k; i++ {
 		c := s.gr.NewExtraCell().(*g.FloatingCellBase)
@@ -122,9 +213,20 @@ func (s *knightScene) Display() error {
 		*c.Loc() = g.FLoc{X: float32(l.X), Y: float32(l.Y)}
 		c.Cell.Alpha = 0
 		s.knights[i].c = c
+		s.knights[i].ILoc = l
 		s.knights[i].P = g.Paint(i)
+		s.knights[i].target = s.palette.Paint(i)
 		s.knights[i].apply()
 	}
+	if s.mode.toward {
+		s.fadeColumn = 0
+		s.fadeMultiplier = float32(s.gr.Width) / knightFadeRate / 6 * 0.003
+		s.toneBase, s.toneOffset = 0, 1
+		for i := range s.knights {
+			s.land(nil, &s.knights[i], i)
+		}
+		s.highlightKnights()
+	}
 	return nil
 }
 
@@ -136,16 +238,46 @@ func (s *knightScene) Hide() error {
 	return nil
 }
 
-func (s *knightScene) Tick(voice *sound.Voice, km keys.Map) (bool, error) {
+// move moves a knight, towards one of the targets if there are any.
+func (s *knightScene) move(k *knight, idx int) {
+	var v g.IVec
+	if len(s.targets) > 0 {
+		v = knightMoveToward(s.gctx.Rand(), s.gr, k.ILoc, s.targets[idx%len(s.targets)])
+	} else {
+		v = knightMove(s.gctx.Rand())
+	}
+	k.ILoc, _ = s.gr.Add(k.ILoc, v)
+}
+
+func (s *knightScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	s.targets = s.targets[:0]
+	for _, p := range pointers {
+		if !p.Down() {
+			continue
+		}
+		if l, c := s.gr.CellAt(p.X, p.Y); c != nil {
+			s.targets = append(s.targets, l)
+		}
+	}
+	if s.mode.toward {
+		for i := range s.knights {
+			s.knights[i].landed++
+		}
+		s.highlightKnights()
+	}
 	s.cycle = (s.cycle + 1) % s.mode.cycleTime
 	if s.cycle != 0 {
 		return false, nil
 	}
+	if s.mode.toward {
+		s.tickToward(voice)
+		return true, nil
+	}
 	s.gr.Iterate(func(gr g.Grid, l g.ILoc, n int, c *g.Cell) {
 		c.IncAlpha(-0.001)
 	})
 	k := &s.knights[s.nextKnight]
-	k.ILoc, _ = s.gr.Add(k.ILoc, knightMove())
+	s.move(k, s.nextKnight)
 	k.P = s.gr.IncP(k.ILoc, 2)
 	k.c.Cell.Alpha = 1
 	k.apply()
@@ -159,6 +291,62 @@ func (s *knightScene) Tick(voice *sound.Voice, km keys.Map) (bool, error) {
 	return true, nil
 }
 
+// land colors the square a knight has landed on with the knight's color,
+// and pulls the squares around it towards that color.
+func (s *knightScene) land(voice *sound.Voice, k *knight, idx int) {
+	c := s.gr.At(k.ILoc)
+	old := c.P
+	c.P = k.target
+	c.Alpha = 1
+	k.P = k.target
+	k.landed = 0
+	k.apply()
+	// every third knight plays a rising series of tones
+	if idx%3 == 0 {
+		voice.Play(s.toneBase+s.toneOffset-1, 100)
+		s.toneOffset = (s.toneOffset % 3) + 1
+		if s.toneOffset == 1 {
+			s.toneBase = (s.toneBase + 1) % 4
+		}
+	}
+	voice.Play(int(old)+5, 70)
+	s.gr.Neighbors(k.ILoc, func(gr g.Grid, l g.ILoc, n int, c *g.Cell) {
+		c.P = s.palette.Toward(c.P, k.target, 1)
+		if c.Alpha < knightFaded {
+			c.Alpha = (knightFaded + c.Alpha) / 2
+		}
+	})
+}
+
+// tickToward moves the next knight, in a mode where knights pull squares
+// towards their own colors.
+func (s *knightScene) tickToward(voice *sound.Voice) {
+	k := &s.knights[s.nextKnight]
+	s.gr.At(k.ILoc).Alpha = knightFaded + 0.1
+	s.move(k, s.nextKnight)
+	s.land(voice, k, s.nextKnight)
+	s.nextKnight = (s.nextKnight + 1) % s.mode.k
+	for i := 0; i < knightFadeRate; i++ {
+		for j := range s.gr.Cells[s.fadeColumn] {
+			c := &s.gr.Cells[s.fadeColumn][j]
+			c.Alpha -= s.fadeMultiplier
+			if c.Alpha < 0.005 {
+				c.Alpha = 0.005
+			}
+		}
+		s.fadeColumn = (s.fadeColumn + 1) % s.gr.Width
+	}
+	s.highlightKnights()
+}
+
+// highlightKnights updates every knight's highlight, since other knights
+// may have changed the squares under them.
+func (s *knightScene) highlightKnights() {
+	for i := range s.knights {
+		s.knights[i].highlight(s.gr.At(s.knights[i].ILoc).P)
+	}
+}
+
 func (s *knightScene) Draw(screen *ebiten.Image) error {
 	s.gctx.Render(screen, func(t *ebiten.Image, scale float32) {
 		s.gr.Draw(t, scale)
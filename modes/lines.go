@@ -0,0 +1,200 @@
+package modes
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// linesMode is one of the internal modes based on lines bouncing around
+// the screen.
+type linesMode struct {
+	cycleTime       int // number of ticks to go by between updates
+	colorMultiplier int // interpolation between palette colors
+	history         int // number of trailing lines to draw
+	thickness       int
+}
+
+const (
+	linesCycleTime = 1
+	// fewest and most points the lines can have
+	linesMinPoints = 2
+	linesMaxPoints = 8
+)
+
+var linesModes = []linesMode{
+	{cycleTime: linesCycleTime, colorMultiplier: 8, history: 24, thickness: 3},
+}
+
+func init() {
+	for _, mode := range linesModes {
+		defaultList.Add(mode)
+	}
+}
+
+func (m linesMode) Name() string {
+	return "lines"
+}
+
+func (m linesMode) Description() string {
+	return "a line segment bounces around the screen leaving trails"
+}
+
+func (m linesMode) Settings() []Setting {
+	return []Setting{
+		cycleTimeSetting(m.cycleTime),
+		colorMultiplierSetting(m.colorMultiplier),
+		intSetting("history", "number of trailing lines to draw", m.history, 1, 256),
+		thicknessSetting(m.thickness),
+	}
+}
+
+func (m linesMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	m.colorMultiplier = v.Int("colorMultiplier")
+	m.history = v.Int("history")
+	m.thickness = v.Int("thickness")
+	return m, nil
+}
+
+func (m linesMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
+	return newLinesScene(m, gctx, detail, p)
+}
+
+type linesScene struct {
+	palette   *g.Palette
+	gctx      *g.Context
+	mode      linesMode
+	detail    int
+	cycle     int
+	bounds    g.Region
+	points    []g.MovingPoint
+	pl        []*g.PolyLine
+	nextColor g.Paint
+}
+
+func newLinesScene(m linesMode, gctx *g.Context, detail int, p *g.Palette) (*linesScene, error) {
+	sc := &linesScene{mode: m, gctx: gctx, detail: detail}
+	_, _, _, cx, cy := gctx.Centered()
+	sc.bounds = g.Region{
+		Min: g.Point{X: -1 - cx, Y: -1 - cy},
+		Max: g.Point{X: 1 + cx, Y: 1 + cy},
+	}
+	err := sc.Reset(detail, p)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (s *linesScene) Mode() Mode {
+	return s.mode
+}
+
+func (s *linesScene) Reset(detail int, p *g.Palette) error {
+	_ = s.Hide()
+	s.detail = detail
+	s.palette = p.Interpolate(s.mode.colorMultiplier)
+	err := s.Display()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *linesScene) Display() error {
+	s.points = s.points[:0]
+	for i := 0; i < linesMinPoints; i++ {
+		s.points = append(s.points, s.newPoint())
+	}
+	s.nextColor = 0
+	s.pl = make([]*g.PolyLine, s.mode.history)
+	for i := range s.pl {
+		pl := s.gctx.NewPolyline(s.mode.thickness, 1, s.palette)
+		pl.Joined = true
+		pl.Blend = false
+		s.pl[i] = pl
+		s.compute(pl)
+		s.move()
+	}
+	return nil
+}
+
+func (s *linesScene) Hide() error {
+	s.pl = nil
+	return nil
+}
+
+// newPoint yields a point at a random location, moving in a random
+// direction.
+func (s *linesScene) newPoint() g.MovingPoint {
+	rng := s.gctx.Rand()
+	min, max := s.bounds.Min, s.bounds.Max
+	pt := g.MovingPoint{
+		Loc: g.Point{
+			X: min.X + rng.Float32()*(max.X-min.X),
+			Y: min.Y + rng.Float32()*(max.Y-min.Y),
+		},
+		Velocity: g.Vec{
+			X: (rng.Float32() - 0.5) * 0.04,
+			Y: (rng.Float32() - 0.5) * 0.04,
+		},
+		Bounds: s.bounds,
+	}
+	return pt
+}
+
+// compute sets pl to the current points, in the next color. With more
+// than two points, the line closes back to the first point.
+func (s *linesScene) compute(pl *g.PolyLine) {
+	pl.Reset()
+	for _, pt := range s.points {
+		pl.Add(pt.Loc.X, pt.Loc.Y, s.nextColor)
+	}
+	if len(s.points) > 2 {
+		pl.Add(s.points[0].Loc.X, s.points[0].Loc.Y, s.nextColor)
+	}
+	s.nextColor = s.palette.Inc(s.nextColor, 1)
+}
+
+// move moves every point, reporting whether any of them bounced.
+func (s *linesScene) move() (bounced bool) {
+	for i := range s.points {
+		if s.points[i].Update() {
+			bounced = true
+		}
+	}
+	return bounced
+}
+
+func (s *linesScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	if km.Pressed(ebiten.KeyA) && len(s.points) < linesMaxPoints {
+		s.points = append(s.points, s.newPoint())
+	}
+	if km.Pressed(ebiten.KeyD) && len(s.points) > linesMinPoints {
+		s.points = s.points[1:]
+	}
+	s.cycle = (s.cycle + 1) % s.mode.cycleTime
+	if s.cycle != 0 {
+		return false, nil
+	}
+	// reuse the oldest line as the newest
+	line := s.pl[0]
+	copy(s.pl, s.pl[1:])
+	s.pl[len(s.pl)-1] = line
+	s.compute(line)
+	if s.move() {
+		voice.Play(int(s.nextColor)/s.mode.colorMultiplier, 70)
+	}
+	return true, nil
+}
+
+func (s *linesScene) Draw(screen *ebiten.Image) error {
+	s.gctx.Render(screen, func(t *ebiten.Image, scale float32) {
+		for i, pl := range s.pl {
+			pl.Draw(t, float32(i+1)/float32(len(s.pl)), scale)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,256 @@
+package modes
+
+import (
+	math "github.com/chewxy/math32"
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// lissajousMode is one of the internal modes based on lines, drawing
+// Lissajous curves.
+type lissajousMode struct {
+	cycleTime       int // number of ticks to go by between updates
+	colorMultiplier int // interpolation between palette colors
+	history         int // number of trailing curves to draw
+	thickness       int
+	deltaDelta      float32 // phase change per update
+	soundDelay      int     // minimum updates between sounds
+}
+
+const (
+	lissajousCycleTime = 1
+	// how far the curve stays from the edges of the screen
+	lissajousInset = 0.98
+	// how far a or b move towards their targets per update
+	lissajousStep = 0.0625
+	// how far keys move a, b, or the phase
+	lissajousNudge      = 0.5
+	lissajousPhaseNudge = math.Pi / 12
+)
+
+var lissajousModes = []lissajousMode{
+	{cycleTime: lissajousCycleTime, colorMultiplier: 8, history: 16, thickness: 3, deltaDelta: 0.01, soundDelay: 10},
+}
+
+func init() {
+	for _, mode := range lissajousModes {
+		defaultList.Add(mode)
+	}
+}
+
+func (m lissajousMode) Name() string {
+	return "lissajous"
+}
+
+func (m lissajousMode) Description() string {
+	return "Lissajous curves, with alpha and beta controlled by keys"
+}
+
+func (m lissajousMode) Settings() []Setting {
+	return []Setting{
+		cycleTimeSetting(m.cycleTime),
+		colorMultiplierSetting(m.colorMultiplier),
+		intSetting("history", "number of trailing curves to draw", m.history, 1, 256),
+		thicknessSetting(m.thickness),
+		floatSetting("deltaDelta", "phase change per update", m.deltaDelta, 0.0001, 1),
+		intSetting("soundDelay", "minimum updates between sounds", m.soundDelay, 0, 600),
+	}
+}
+
+func (m lissajousMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	m.colorMultiplier = v.Int("colorMultiplier")
+	m.history = v.Int("history")
+	m.thickness = v.Int("thickness")
+	m.deltaDelta = v.Float("deltaDelta")
+	m.soundDelay = v.Int("soundDelay")
+	return m, nil
+}
+
+func (m lissajousMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
+	return newLissajousScene(m, gctx, detail, p)
+}
+
+type lissajousScene struct {
+	palette          *g.Palette
+	gctx             *g.Context
+	mode             lissajousMode
+	detail           int
+	cycle            int
+	pl               []*g.PolyLine
+	nextColor        g.Paint
+	a, b             float32
+	targetA, targetB float32
+	scaleA, scaleB   float32 // speed at which a and b approach their targets
+	delta            float32
+	xScale, yScale   float32
+	signX, signY     []bool
+	soundCooldown    int
+}
+
+func newLissajousScene(m lissajousMode, gctx *g.Context, detail int, p *g.Palette) (*lissajousScene, error) {
+	sc := &lissajousScene{mode: m, gctx: gctx, detail: detail}
+	_, _, _, cx, cy := gctx.Centered()
+	sc.xScale, sc.yScale = (1+cx)*lissajousInset, (1+cy)*lissajousInset
+	err := sc.Reset(detail, p)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (s *lissajousScene) Mode() Mode {
+	return s.mode
+}
+
+func (s *lissajousScene) Reset(detail int, p *g.Palette) error {
+	_ = s.Hide()
+	s.detail = detail
+	s.palette = p.Interpolate(s.mode.colorMultiplier)
+	err := s.Display()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *lissajousScene) Display() error {
+	points := s.palette.Length + 1
+	s.signX, s.signY = make([]bool, points), make([]bool, points)
+	s.a, s.b = 2, 3
+	s.targetA, s.targetB = 2, 3
+	s.scaleA, s.scaleB = 1, 1
+	s.delta = 1
+	s.nextColor = 0
+	s.soundCooldown = 0
+	s.pl = make([]*g.PolyLine, s.mode.history)
+	for i := range s.pl {
+		pl := s.gctx.NewPolyline(s.mode.thickness, 1, s.palette)
+		pl.Joined = true
+		pl.Points = make([]g.LinePoint, points)
+		s.pl[i] = pl
+		s.compute(pl, true)
+	}
+	return nil
+}
+
+func (s *lissajousScene) Hide() error {
+	s.pl = nil
+	return nil
+}
+
+// approach moves v towards target by at most lissajousStep*scale.
+func approach(v, target, scale float32) float32 {
+	if v < target {
+		return math.Min(target, v+lissajousStep*scale)
+	}
+	return math.Max(target, v-lissajousStep*scale)
+}
+
+// retarget sets new targets for a and b, avoiding ratios which would
+// just retrace the same curve.
+func (s *lissajousScene) retarget(ta, tb float32) {
+	signA := float32(1)
+	if ta < 0 {
+		signA = -1
+	}
+	if math.Abs(ta) < 1 {
+		ta = signA
+	}
+	if tb < 1 {
+		tb = 1
+	}
+	integerA := math.Mod(ta, 1) == 0 || math.Mod(ta, tb) == 0
+	multiples := math.Mod(ta, tb) == 0 || math.Mod(tb, ta) == 0
+	// if either is a multiple of the other, and neither is 1 exactly,
+	// we'll get redraw/overlap which looks lame
+	if multiples && math.Abs(ta) > 1 && tb > 1 {
+		if integerA {
+			ta += 0.5 * signA
+		} else {
+			tb += 0.5
+		}
+	}
+	s.scaleA = math.Max(1, math.Abs(ta-s.a))
+	s.scaleB = math.Max(1, math.Abs(tb-s.b))
+	s.targetA, s.targetB = ta, tb
+}
+
+// compute moves a and b towards their targets, and computes the curve
+// into pl. It reports whether any of the curve's color boundaries
+// crossed an axis, unless quiet is set.
+func (s *lissajousScene) compute(pl *g.PolyLine, quiet bool) (crossed bool) {
+	s.a = approach(s.a, s.targetA, s.scaleA)
+	s.b = approach(s.b, s.targetB, s.scaleB)
+	lines := len(pl.Points) - 1
+	lineScale := math.Pi * 2 / float32(lines)
+	color := s.nextColor
+	for i := range pl.Points {
+		t := float32(i+1) * lineScale
+		x := math.Sin(s.a*t + s.delta)
+		y := math.Sin(s.b*t - s.delta)
+		if !quiet && i%s.mode.colorMultiplier == 0 {
+			if (x < 0) != s.signX[i] || (y < 0) != s.signY[i] {
+				crossed = true
+			}
+			s.signX[i], s.signY[i] = x < 0, y < 0
+		}
+		pt := &pl.Points[i]
+		pt.X, pt.Y = x*s.xScale, y*s.yScale
+		pt.P = color
+		color = s.palette.Inc(color, 1)
+	}
+	pl.Dirty()
+	s.nextColor = s.palette.Inc(s.nextColor, 1)
+	deltaScale := math.Max(math.Max(math.Abs(s.a), math.Abs(s.b)), 1)
+	s.delta += s.mode.deltaDelta / deltaScale
+	if s.delta > math.Pi*2 {
+		s.delta -= math.Pi * 2
+	}
+	return crossed
+}
+
+func (s *lissajousScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	switch {
+	case km.Pressed(ebiten.KeyA):
+		s.retarget(s.targetA-lissajousNudge, s.targetB)
+	case km.Pressed(ebiten.KeyD):
+		s.retarget(s.targetA+lissajousNudge, s.targetB)
+	case km.Pressed(ebiten.KeyS):
+		s.retarget(s.targetA, s.targetB-lissajousNudge)
+	case km.Pressed(ebiten.KeyW):
+		s.retarget(s.targetA, s.targetB+lissajousNudge)
+	}
+	if km.Pressed(ebiten.KeyComma) {
+		s.delta -= lissajousPhaseNudge
+	}
+	if km.Pressed(ebiten.KeyPeriod) {
+		s.delta += lissajousPhaseNudge
+	}
+	s.cycle = (s.cycle + 1) % s.mode.cycleTime
+	if s.cycle != 0 {
+		return false, nil
+	}
+	// reuse the oldest curve as the newest
+	line := s.pl[0]
+	copy(s.pl, s.pl[1:])
+	s.pl[len(s.pl)-1] = line
+	if s.compute(line, false) && s.soundCooldown < 1 {
+		voice.Play(int(s.nextColor)/s.mode.colorMultiplier, 70)
+		s.soundCooldown = s.mode.soundDelay
+	} else {
+		s.soundCooldown--
+	}
+	return true, nil
+}
+
+func (s *lissajousScene) Draw(screen *ebiten.Image) error {
+	s.gctx.Render(screen, func(t *ebiten.Image, scale float32) {
+		for i, pl := range s.pl {
+			pl.Draw(t, math.Sqrt(float32(i+1)/float32(len(s.pl))), scale)
+		}
+	})
+	return nil
+}
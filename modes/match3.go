@@ -2,7 +2,6 @@ package modes
 
 import (
 	"fmt"
-	"math/rand"
 
 	"github.com/hajimehoshi/ebiten"
 	"seebs.net/modus/g"
@@ -37,6 +36,15 @@ func (m match3Mode) Description() string {
 	return "match3 thing"
 }
 
+func (m match3Mode) Settings() []Setting {
+	return []Setting{cycleTimeSetting(m.cycleTime)}
+}
+
+func (m match3Mode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	return m, nil
+}
+
 func (m match3Mode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
 	scale, ox, oy, _, _ := gctx.Centered()
 	return newMatch3Scene(m, gctx, detail, p, scale, ox, oy)
@@ -65,6 +73,7 @@ type match3Scene struct {
 	splashy      *g.ParticleSystem
 	toneOffset   int
 	particleShim g.Affine
+	touches      keys.Pointers // released pointers waiting for the board to settle
 }
 
 func newMatch3Scene(m match3Mode, gctx *g.Context, detail int, p *g.Palette, scale, offsetX, offsetY float32) (*match3Scene, error) {
@@ -85,6 +94,7 @@ func (s *match3Scene) Mode() Mode {
 
 func (s *match3Scene) Reset(detail int, p *g.Palette) error {
 	_ = s.Hide()
+	s.detail = detail
 	s.palette = p
 	err := s.Display()
 	if err != nil {
@@ -101,15 +111,21 @@ func (s *match3Scene) Display() error {
 		s.matching[i] = make([]bool, len(s.gr.Cells[i]))
 	}
 	s.gr.Iterate(func(generic g.Grid, l g.ILoc, n int, c *g.Cell) {
-		c.P = g.Paint(rand.Int31n(6))
+		c.P = g.Paint(s.gctx.Rand().Int31n(6))
 		c.Scale = match3GridScale
 		c.Alpha = 1.0
 	})
+	// anything in progress refers to the old grid
+	s.fading, s.erased, s.moving = nil, nil, nil
+	s.matchCount, s.fadeDir, s.fallSpeed = 0, 0, 0
+	s.explode = false
+	s.touches = nil
 	return nil
 }
 
 func (s *match3Scene) Hide() error {
 	s.gr = nil
+	s.splashy = nil
 	return nil
 }
 
@@ -181,8 +197,45 @@ func (s *match3Scene) explodeColor(exploding g.Paint) int {
 	return matches
 }
 
+// queueTouches saves released pointers, which only last a frame, until
+// the board settles and touch can apply them.
+func (s *match3Scene) queueTouches(pointers keys.Pointers) {
+	for _, p := range pointers {
+		if p.Released() {
+			s.touches = append(s.touches, p)
+		}
+	}
+}
+
+// touch handles queued pointer gestures: tapping a hex makes its color the
+// next one to match, and dragging from one hex to a neighbor swaps them.
+func (s *match3Scene) touch(voice *sound.Voice) {
+	for _, p := range s.touches {
+		from, fc := s.gr.CellAt(p.SX, p.SY)
+		to, tc := s.gr.CellAt(p.X, p.Y)
+		if fc == nil || tc == nil {
+			continue
+		}
+		if from == to {
+			s.nextMatch = fc.P
+			voice.Play((int(s.nextMatch)+s.toneOffset)%15, 60)
+			continue
+		}
+		for d := g.HexDir(0); d < 6; d++ {
+			if c, l := s.gr.Neighbor(from, d, false); c != nil && l == to {
+				fc.P, tc.P = tc.P, fc.P
+				voice.Play((int(fc.P)+s.toneOffset)%15, 60)
+				break
+			}
+		}
+	}
+	s.touches = s.touches[:0]
+}
+
 //
-func (s *match3Scene) Tick(voice *sound.Voice, km keys.Map) (bool, error) {
+func (s *match3Scene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	rng := s.gctx.Rand()
+	s.queueTouches(pointers)
 	s.cycle = (s.cycle + 1) % s.mode.cycleTime
 	if s.cycle != 0 {
 		return false, nil
@@ -263,7 +316,7 @@ func (s *match3Scene) Tick(voice *sound.Voice, km keys.Map) (bool, error) {
 			for gone.HexCell != nil {
 				gone.HexCell.Dir = dir
 				gone.HexCell.Dist = float32(skipped)
-				gone.HexCell.P = g.Paint(rand.Int31n(6))
+				gone.HexCell.P = g.Paint(rng.Int31n(6))
 				gone.HexCell.Alpha = 1.0
 				addedCells = append(addedCells, gone)
 				s.moving = append(s.moving, gone)
@@ -278,7 +331,7 @@ func (s *match3Scene) Tick(voice *sound.Voice, km keys.Map) (bool, error) {
 		for s.getMatches(false) == 0 {
 			counter++
 			for _, c := range addedCells {
-				c.P = g.Paint(rand.Int31n(6))
+				c.P = g.Paint(rng.Int31n(6))
 			}
 			// give up for now.
 			if counter > 1000 {
@@ -314,6 +367,7 @@ func (s *match3Scene) Tick(voice *sound.Voice, km keys.Map) (bool, error) {
 			s.gr.Status = fmt.Sprintf("moving %d hexes, dist %.2f", n, s.moving[0].Dist)
 		}
 	default:
+		s.touch(voice)
 		s.matchCount = s.getMatches(true)
 		if s.matchCount > 0 {
 			s.gr.Status = fmt.Sprintf("found %d matches", s.matchCount)
@@ -343,26 +397,26 @@ func (s *match3Scene) Tick(voice *sound.Voice, km keys.Map) (bool, error) {
 			params := g.ParticleParams{
 				State: g.ParticleState{
 					P:     c.P,
-					Scale: rand.Float32()/2 + 0.5,
+					Scale: rng.Float32()/2 + 0.5,
 				},
 			}
 			for i := 0; i < 5; i++ {
-				params.State.X = x0 + (rand.Float32()-0.5)/24
-				params.State.Y = y0 + (rand.Float32()-0.5)/24
-				params.Delta.X = (rand.Float32() - 0.5) / 4
-				params.Delta.Y = (rand.Float32() - 0.5) / 4
-				params.State.Scale = rand.Float32()/2 + 0.5
-				params.Delay = int(rand.Int31n(6))
+				params.State.X = x0 + (rng.Float32()-0.5)/24
+				params.State.Y = y0 + (rng.Float32()-0.5)/24
+				params.Delta.X = (rng.Float32() - 0.5) / 4
+				params.Delta.Y = (rng.Float32() - 0.5) / 4
+				params.State.Scale = rng.Float32()/2 + 0.5
+				params.Delay = int(rng.Int31n(6))
 				_ = s.splashy.Add(params)
 			}
 			for i := 0; i < 3; i++ {
-				params.State.X = x0 + (rand.Float32()-0.5)/24
-				params.State.Y = y0 + (rand.Float32()-0.5)/24
-				params.Delta.X = (rand.Float32() - 0.5) / 4
-				params.Delta.Y = (rand.Float32() - 0.5) / 4
-				params.State.Scale = rand.Float32()/2 + 0.5
-				params.Delay = int(rand.Int31n(6))
-				params.State.P = c.P + g.Paint(rand.Int31n(5)) + 1
+				params.State.X = x0 + (rng.Float32()-0.5)/24
+				params.State.Y = y0 + (rng.Float32()-0.5)/24
+				params.Delta.X = (rng.Float32() - 0.5) / 4
+				params.Delta.Y = (rng.Float32() - 0.5) / 4
+				params.State.Scale = rng.Float32()/2 + 0.5
+				params.Delay = int(rng.Int31n(6))
+				params.State.P = c.P + g.Paint(rng.Int31n(5)) + 1
 				_ = s.splashy.Add(params)
 			}
 		}
@@ -0,0 +1,33 @@
+package modes
+
+import (
+	"testing"
+
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+)
+
+func TestMatch3QueuesTouches(t *testing.T) {
+	c := g.NewContext(1280, 960, false)
+	scene, err := match3Mode{cycleTime: 4}.New(c, 10, g.Palettes["rainbow"])
+	if err != nil {
+		t.Fatalf("creating scene: %v", err)
+	}
+	s := scene.(*match3Scene)
+	tap := keys.Pointers{{ID: keys.MouseID, State: keys.RELEASE, X: 640, Y: 480, SX: 640, SY: 480}}
+	// the first tick isn't an update tick, so the tap has to be queued
+	if _, err := s.Tick(nil, nil, tap); err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+	if len(s.touches) != 1 {
+		t.Fatalf("expected 1 queued touch, got %d", len(s.touches))
+	}
+	for i := 0; len(s.touches) > 0; i++ {
+		if i > 10000 {
+			t.Fatalf("queued touch never applied")
+		}
+		if _, err := s.Tick(nil, nil, nil); err != nil {
+			t.Fatalf("tick: %v", err)
+		}
+	}
+}
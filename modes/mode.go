@@ -1,6 +1,7 @@
 package modes
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/hajimehoshi/ebiten"
@@ -30,8 +31,9 @@ type Scene interface {
 	// number of squares across that a grid should be.
 	Reset(detail int, p *g.Palette) error
 	// Tick updates the mode's internal state, such as moving objects
-	// around the screen.
-	Tick(voice *sound.Voice, km keys.Map) (bool, error)
+	// around the screen. It is called every frame, with the current key
+	// and pointer states.
+	Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error)
 	// Draw renders the mode's internal state graphically to the provided
 	// screen.
 	Draw(screen *ebiten.Image) error
@@ -113,6 +115,52 @@ func (ml *ModeList) Add(m Mode) {
 	ml.list = append(ml.list, m)
 }
 
+// Find yields the mode with the given name, or nil if there isn't one.
+func (ml *ModeList) Find(name string) Mode {
+	for _, mode := range ml.list {
+		if mode.Name() == name {
+			return mode
+		}
+	}
+	return nil
+}
+
+// DetailLevels are the detail levels StepDetail moves between.
+var DetailLevels = []int{5, 10, 15, 20, 25, 30, 40, 50}
+
+// StepDetail yields the detail level n levels above detail, or below it
+// for negative n, stopping at the ends of DetailLevels. A detail level
+// between two levels steps to the nearest one in that direction. Other
+// front ends can use this, and g.StepPalette, with Scene.Reset to offer
+// the same controls as cmd/modus.
+func StepDetail(detail, n int) int {
+	// i is the index of the highest level at or below detail
+	i := sort.SearchInts(DetailLevels, detail+1) - 1
+	switch {
+	case n > 0:
+		i += n
+	case n < 0:
+		if i >= 0 && DetailLevels[i] < detail {
+			i++
+		}
+		i += n
+	default:
+		return detail
+	}
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(DetailLevels) {
+		i = len(DetailLevels) - 1
+	}
+	// don't step the wrong way from outside the range of levels
+	level := DetailLevels[i]
+	if (n > 0 && level < detail) || (n < 0 && level > detail) {
+		return detail
+	}
+	return level
+}
+
 var defaultFilter ModeFilter
 var defaultList ModeList
 
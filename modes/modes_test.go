@@ -12,7 +12,7 @@ import (
 
 func benchmarkOneModeTick(b *testing.B, scene Scene) {
 	for i := 0; i < b.N; i++ {
-		_, err := scene.Tick(nil, nil)
+		_, err := scene.Tick(nil, nil, nil)
 		if err != nil {
 			b.Fatalf("error in tick: %v", err)
 		}
@@ -21,7 +21,7 @@ func benchmarkOneModeTick(b *testing.B, scene Scene) {
 
 func benchmarkOneModeTickDraw(b *testing.B, scene Scene) {
 	for i := 0; i < b.N; i++ {
-		_, err := scene.Tick(nil, nil)
+		_, err := scene.Tick(nil, nil, nil)
 		if err != nil {
 			b.Fatalf("error in tick: %v", err)
 		}
@@ -87,6 +87,99 @@ func Benchmark_ModeDraw(b *testing.B) {
 	}
 }
 
+// gridSize yields the dimensions of a scene's grid, if it has one. For
+// composite scenes, that's the top layer's grid.
+func gridSize(scene Scene) (w, h int, ok bool) {
+	switch s := scene.(type) {
+	case *cascadeScene:
+		return s.gr.Width, s.gr.Height, true
+	case *cascade2Scene:
+		return s.gr.Width, s.gr.Height, true
+	case *fireScene:
+		return s.gr.Width, s.gr.Height, true
+	case *firebugsScene:
+		return s.gr.Width, s.gr.Height, true
+	case *knightScene:
+		return s.gr.Width, s.gr.Height, true
+	case *hexPaintScene:
+		return s.gr.Width, s.gr.Height, true
+	case *match3Scene:
+		return s.gr.Width, s.gr.Height, true
+	case *wanderingScene:
+		return s.gr.Width, s.gr.Height, true
+	case *dotGridScene:
+		return s.gr.W, s.gr.H, true
+	case *compositeScene:
+		return gridSize(s.scenes[len(s.scenes)-1])
+	}
+	return 0, 0, false
+}
+
+func TestModeReset(t *testing.T) {
+	c := g.NewContext(1280, 960, false)
+	p := g.Palettes["rainbow"]
+	for _, mode := range ListModes() {
+		scene, err := mode.New(c, 10, p)
+		if err != nil {
+			t.Fatalf("failed to initialize scene %s: %v", mode.Name(), err)
+		}
+		prevW := -1
+		for _, detail := range detailLevels {
+			// a fresh scene at this detail level gives the grid size a
+			// reset one should have
+			fresh, err := mode.New(c, detail, p)
+			if err != nil {
+				t.Fatalf("failed to initialize scene %s@%d: %v", mode.Name(), detail, err)
+			}
+			freshW, freshH, hasGrid := gridSize(fresh)
+			for _, palette := range g.PaletteNames() {
+				err = scene.Reset(detail, g.Palettes[palette])
+				if err != nil {
+					t.Fatalf("failed to reset scene %s@%d/%s: %v", mode.Name(), detail, palette, err)
+				}
+				if w, h, _ := gridSize(scene); hasGrid && (w != freshW || h != freshH) {
+					t.Errorf("scene %s@%d/%s: expected %dx%d grid after reset, got %dx%d", mode.Name(), detail, palette, freshW, freshH, w, h)
+				}
+				for i := 0; i < 10; i++ {
+					_, err = scene.Tick(nil, nil, nil)
+					if err != nil {
+						t.Fatalf("error in tick %s@%d/%s: %v", mode.Name(), detail, palette, err)
+					}
+				}
+			}
+			if hasGrid && freshW == prevW {
+				t.Errorf("scene %s@%d: grid width %d didn't change with detail level", mode.Name(), detail, freshW)
+			}
+			prevW = freshW
+		}
+	}
+}
+
+func TestStepDetail(t *testing.T) {
+	cases := []struct {
+		detail, n, expected int
+	}{
+		{20, 1, 25},
+		{20, -1, 15},
+		{22, 1, 25},
+		{22, -1, 20},
+		{20, 2, 30},
+		{20, 0, 20},
+		{5, -1, 5},
+		{50, 1, 50},
+		{3, -1, 3},
+		{3, 1, 5},
+		{80, 1, 80},
+		{80, -1, 50},
+	}
+	for _, c := range cases {
+		got := StepDetail(c.detail, c.n)
+		if got != c.expected {
+			t.Errorf("StepDetail(%d, %d): expected %d, got %d", c.detail, c.n, c.expected, got)
+		}
+	}
+}
+
 type gravityTestWrapper struct {
 	scene          *dotGridScene
 	modeName       string
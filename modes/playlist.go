@@ -0,0 +1,173 @@
+package modes
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"seebs.net/modus/g"
+)
+
+// TicksPerSecond is the rate at which scenes are ticked, used to convert
+// playlist durations given in seconds.
+const TicksPerSecond = 60
+
+// PlaylistEntry is a single entry in a playlist: a mode, and how to run it.
+type PlaylistEntry struct {
+	Mode    Mode
+	Ticks   int    // how long to run the mode
+	Detail  int    // detail level, or 0 for the default
+	Palette string // name of a palette in g.Palettes, or "" for the default
+}
+
+// Playlist cycles through a list of modes, running each for a fixed
+// number of ticks. When it reaches the end, it starts over from the
+// top, reshuffling first if Shuffle is set.
+type Playlist struct {
+	Entries   []PlaylistEntry
+	Shuffle   bool
+	order     []int
+	pos       int
+	remaining int
+}
+
+// NewPlaylist creates a playlist running each of the given modes, such as
+// the results of ListModes, for the given number of ticks.
+func NewPlaylist(modes []Mode, ticks int) *Playlist {
+	pl := &Playlist{}
+	for _, mode := range modes {
+		pl.Entries = append(pl.Entries, PlaylistEntry{Mode: mode, Ticks: ticks})
+	}
+	return pl
+}
+
+// A playlist file is a line-oriented text file, with one entry per line.
+// Each entry gives a mode name, followed by an optional duration, detail
+// level, and palette name. Durations ending in s are in seconds, and
+// otherwise in ticks. A dash leaves a field at its default. A line
+// containing only "shuffle" shuffles the playlist. Anything after a #
+// is ignored.
+//
+//	shuffle
+//	# mode    duration  detail  palette
+//	knights3  30s       20      rainbow
+//	lines     600
+//	spiral1   -         10
+
+// ReadPlaylist reads a playlist file. Entries whose duration isn't given
+// run for the given number of ticks. Mode names must be found in modes,
+// so a playlist can't name modes which have been filtered out.
+func ReadPlaylist(r io.Reader, modes []Mode, ticks int) (*Playlist, error) {
+	byName := make(map[string]Mode, len(modes))
+	for _, mode := range modes {
+		byName[mode.Name()] = mode
+	}
+	pl := &Playlist{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) == 1 && fields[0] == "shuffle" {
+			pl.Shuffle = true
+			continue
+		}
+		if len(fields) > 4 {
+			return nil, fmt.Errorf("line %d: expected mode, duration, detail, and palette", line)
+		}
+		for len(fields) < 4 {
+			fields = append(fields, "-")
+		}
+		e := PlaylistEntry{Mode: byName[fields[0]], Ticks: ticks}
+		if e.Mode == nil {
+			return nil, fmt.Errorf("line %d: unknown mode %q", line, fields[0])
+		}
+		if fields[1] != "-" {
+			var err error
+			e.Ticks, err = parseDuration(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+		}
+		if fields[2] != "-" {
+			var err error
+			e.Detail, err = strconv.Atoi(fields[2])
+			if err != nil || e.Detail < 1 {
+				return nil, fmt.Errorf("line %d: invalid detail level %q", line, fields[2])
+			}
+		}
+		if fields[3] != "-" {
+			if g.Palettes[fields[3]] == nil {
+				return nil, fmt.Errorf("line %d: unknown palette %q", line, fields[3])
+			}
+			e.Palette = fields[3]
+		}
+		pl.Entries = append(pl.Entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(pl.Entries) == 0 {
+		return nil, fmt.Errorf("no entries in playlist")
+	}
+	return pl, nil
+}
+
+// parseDuration parses a duration as either seconds, with a trailing s,
+// or ticks.
+func parseDuration(s string) (int, error) {
+	scale := 1
+	n := s
+	if strings.HasSuffix(s, "s") {
+		scale = TicksPerSecond
+		n = s[:len(s)-1]
+	}
+	v, err := strconv.ParseFloat(n, 64)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	ticks := int(v * float64(scale))
+	if ticks < 1 {
+		ticks = 1
+	}
+	return ticks, nil
+}
+
+// Next advances to the next entry, and returns it. The first call yields
+// the first entry. rng is used to shuffle the playlist at the start of
+// each pass through it, if Shuffle is set.
+func (pl *Playlist) Next(rng *rand.Rand) PlaylistEntry {
+	if pl.pos >= len(pl.order) {
+		pl.order = pl.order[:0]
+		for i := range pl.Entries {
+			pl.order = append(pl.order, i)
+		}
+		if pl.Shuffle {
+			rng.Shuffle(len(pl.order), func(i, j int) {
+				pl.order[i], pl.order[j] = pl.order[j], pl.order[i]
+			})
+		}
+		pl.pos = 0
+	}
+	e := pl.Entries[pl.order[pl.pos]]
+	pl.pos++
+	pl.remaining = e.Ticks
+	return e
+}
+
+// Tick counts down the current entry's duration, reporting true once it
+// has run out, at which point the caller should move on with Next.
+func (pl *Playlist) Tick() bool {
+	pl.remaining--
+	return pl.remaining <= 0
+}
@@ -0,0 +1,78 @@
+package modes
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestReadPlaylist(t *testing.T) {
+	available := []Mode{linesModes[0], lissajousModes[0], stringArtModes[0]}
+	pl, err := ReadPlaylist(strings.NewReader(`
+# mode      duration  detail  palette
+lines       2s        12      rainbow
+lissajous   5
+stringart   -         8   # comment
+`), available, 100)
+	if err != nil {
+		t.Fatalf("reading playlist: unexpected error: %v", err)
+	}
+	expected := []struct {
+		name    string
+		ticks   int
+		detail  int
+		palette string
+	}{
+		{"lines", 2 * TicksPerSecond, 12, "rainbow"},
+		{"lissajous", 5, 0, ""},
+		{"stringart", 100, 8, ""},
+	}
+	rng := rand.New(rand.NewSource(1))
+	// go through twice, to check that it restarts from the top
+	for pass := 0; pass < 2; pass++ {
+		for i, exp := range expected {
+			e := pl.Next(rng)
+			if e.Mode.Name() != exp.name || e.Ticks != exp.ticks || e.Detail != exp.detail || e.Palette != exp.palette {
+				t.Errorf("pass %d, entry %d: expected %v, got %s/%d/%d/%s", pass, i, exp, e.Mode.Name(), e.Ticks, e.Detail, e.Palette)
+			}
+			for j := 1; j < e.Ticks; j++ {
+				if pl.Tick() {
+					t.Fatalf("pass %d, entry %d: ended after %d of %d ticks", pass, i, j, e.Ticks)
+				}
+			}
+			if !pl.Tick() {
+				t.Fatalf("pass %d, entry %d: didn't end after %d ticks", pass, i, e.Ticks)
+			}
+		}
+	}
+
+	bad := []string{
+		"nosuchmode 5",
+		"lines 0",
+		"lines 5x",
+		"lines 5 0",
+		"lines 5 5 nosuchpalette",
+		"lines 5 5 rainbow extra",
+		"# nothing",
+	}
+	for _, b := range bad {
+		if _, err := ReadPlaylist(strings.NewReader(b), available, 100); err == nil {
+			t.Errorf("%q: expected error, got none", b)
+		}
+	}
+}
+
+func TestPlaylistShuffle(t *testing.T) {
+	pl := NewPlaylist([]Mode{linesModes[0], lissajousModes[0], stringArtModes[0], raindropsModes[0]}, 1)
+	pl.Shuffle = true
+	rng := rand.New(rand.NewSource(1))
+	for pass := 0; pass < 4; pass++ {
+		seen := make(map[string]bool)
+		for range pl.Entries {
+			seen[pl.Next(rng).Mode.Name()] = true
+		}
+		if len(seen) != len(pl.Entries) {
+			t.Errorf("pass %d: expected each of %d entries once, got %v", pass, len(pl.Entries), seen)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package modes
+
+import (
+	"fmt"
+
+	math "github.com/chewxy/math32"
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// raindropsMode is one of the internal modes based on lines, drawing
+// rings splashing out from raindrops.
+type raindropsMode struct {
+	cycleTime     int // number of ticks to go by between updates
+	drops         int // total drops, which are reused once they fade
+	dropThreshold int // a drop only falls when rand(spare drops) exceeds this
+	minCooldown   int // range of updates between drops
+	maxCooldown   int
+	minGrowth     int // range of updates a drop lasts
+	maxGrowth     int
+}
+
+const (
+	raindropsCycleTime = 2
+	// segments in each ring
+	raindropsSegments = 32
+	// radius of a drop at scale 1
+	raindropsRadius = 0.4
+	// how far from the edges of the screen drops land
+	raindropsInset = 0.15
+)
+
+var raindropsModes = []raindropsMode{
+	{cycleTime: raindropsCycleTime, drops: 12, dropThreshold: 2, minCooldown: 6, maxCooldown: 30, minGrowth: 40, maxGrowth: 90},
+}
+
+func init() {
+	for _, mode := range raindropsModes {
+		defaultList.Add(mode)
+	}
+}
+
+func (m raindropsMode) Name() string {
+	return "raindrops"
+}
+
+func (m raindropsMode) Description() string {
+	return "raindrops splash and fade"
+}
+
+func (m raindropsMode) Settings() []Setting {
+	return []Setting{
+		cycleTimeSetting(m.cycleTime),
+		intSetting("drops", "total drops, which are reused once they fade", m.drops, 1, 256),
+		intSetting("dropThreshold", "spare drops needed before a drop can fall", m.dropThreshold, 0, 256),
+		intSetting("minCooldown", "fewest updates between drops", m.minCooldown, 1, 600),
+		intSetting("maxCooldown", "most updates between drops", m.maxCooldown, 1, 600),
+		intSetting("minGrowth", "fewest updates a drop lasts", m.minGrowth, 1, 600),
+		intSetting("maxGrowth", "most updates a drop lasts", m.maxGrowth, 1, 600),
+	}
+}
+
+func (m raindropsMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	m.drops = v.Int("drops")
+	m.dropThreshold = v.Int("dropThreshold")
+	m.minCooldown = v.Int("minCooldown")
+	m.maxCooldown = v.Int("maxCooldown")
+	m.minGrowth = v.Int("minGrowth")
+	m.maxGrowth = v.Int("maxGrowth")
+	if m.dropThreshold >= m.drops {
+		return nil, fmt.Errorf("dropThreshold (%d) must be less than drops (%d), or no drops can fall", m.dropThreshold, m.drops)
+	}
+	if m.minCooldown >= m.maxCooldown {
+		return nil, fmt.Errorf("minCooldown (%d) must be less than maxCooldown (%d)", m.minCooldown, m.maxCooldown)
+	}
+	if m.minGrowth >= m.maxGrowth {
+		return nil, fmt.Errorf("minGrowth (%d) must be less than maxGrowth (%d)", m.minGrowth, m.maxGrowth)
+	}
+	return m, nil
+}
+
+func (m raindropsMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
+	return newRaindropsScene(m, gctx, detail, p)
+}
+
+// raindropsRing holds the unit circle the rings are drawn from.
+var raindropsRing [raindropsSegments + 1]g.Point
+
+func init() {
+	for i := range raindropsRing {
+		theta := math.Pi * 2 * float32(i) / raindropsSegments
+		raindropsRing[i].Y, raindropsRing[i].X = math.Sincos(theta)
+	}
+}
+
+// raindrop is a single drop, with an inner and outer ring, which grow
+// at different rates.
+type raindrop struct {
+	g.Point
+	hue, octave            int
+	scale, inner, outer    float32
+	innerAlpha, outerAlpha float32
+	factor                 float32 // how big this drop is, from 0 to 0.2
+	growth, maxGrowth      int
+	innerRing, outerRing   *g.PolyLine
+}
+
+// setRing sets pl to a circle of radius r around the drop.
+func (d *raindrop) setRing(pl *g.PolyLine, r float32) {
+	for i, pt := range raindropsRing {
+		lp := &pl.Points[i]
+		lp.X, lp.Y = d.X+pt.X*r, d.Y+pt.Y*r
+	}
+	pl.Dirty()
+}
+
+// setScale updates the drop's scales, and the rings to match.
+func (d *raindrop) setScale(scale, inner, outer float32) {
+	d.scale, d.inner, d.outer = scale, inner, outer
+	d.setRing(d.innerRing, raindropsRadius*scale*inner)
+	d.setRing(d.outerRing, raindropsRadius*scale*outer)
+}
+
+type raindropsScene struct {
+	palette      *g.Palette
+	gctx         *g.Context
+	mode         raindropsMode
+	detail       int
+	cycle        int
+	bounds       g.Region
+	drops        []*raindrop // drops currently splashing
+	spares       []*raindrop
+	futureDrops  []g.Point
+	dropCooldown int
+	lastHue      int
+}
+
+func newRaindropsScene(m raindropsMode, gctx *g.Context, detail int, p *g.Palette) (*raindropsScene, error) {
+	sc := &raindropsScene{mode: m, gctx: gctx, detail: detail}
+	_, _, _, cx, cy := gctx.Centered()
+	sc.bounds = g.Region{
+		Min: g.Point{X: -1 - cx + raindropsInset, Y: -1 - cy + raindropsInset},
+		Max: g.Point{X: 1 + cx - raindropsInset, Y: 1 + cy - raindropsInset},
+	}
+	err := sc.Reset(detail, p)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (s *raindropsScene) Mode() Mode {
+	return s.mode
+}
+
+func (s *raindropsScene) Reset(detail int, p *g.Palette) error {
+	_ = s.Hide()
+	s.detail = detail
+	s.palette = p
+	err := s.Display()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *raindropsScene) newRing(p g.Paint, thickness int) *g.PolyLine {
+	pl := s.gctx.NewPolyline(thickness, 1, s.palette)
+	pl.Joined = true
+	pl.Blend = false
+	pl.Points = make([]g.LinePoint, len(raindropsRing))
+	for i := range pl.Points {
+		pl.Points[i].P = p
+	}
+	return pl
+}
+
+func (s *raindropsScene) Display() error {
+	s.drops = s.drops[:0]
+	s.spares = s.spares[:0]
+	for i := 0; i < s.mode.drops; i++ {
+		d := &raindrop{
+			hue:    i % s.palette.Length,
+			octave: i / s.palette.Length,
+		}
+		d.innerRing = s.newRing(s.palette.Paint(d.hue), 4)
+		d.innerRing.SetGlow(true)
+		d.outerRing = s.newRing(s.palette.Paint(d.hue), 2)
+		s.spares = append(s.spares, d)
+	}
+	s.futureDrops = s.futureDrops[:0]
+	s.dropCooldown = 0
+	s.lastHue = -1
+	return nil
+}
+
+func (s *raindropsScene) Hide() error {
+	s.drops = nil
+	s.spares = nil
+	return nil
+}
+
+// randomPoint yields a random point for a drop to land on.
+func (s *raindropsScene) randomPoint() g.Point {
+	rng := s.gctx.Rand()
+	min, max := s.bounds.Min, s.bounds.Max
+	return g.Point{
+		X: min.X + rng.Float32()*(max.X-min.X),
+		Y: min.Y + rng.Float32()*(max.Y-min.Y),
+	}
+}
+
+// grow grows every splashing drop, returning faded drops to the spares.
+func (s *raindropsScene) grow() {
+	n := 0
+	for _, d := range s.drops {
+		d.growth++
+		if d.growth >= d.maxGrowth {
+			s.spares = append(s.spares, d)
+			continue
+		}
+		d.setScale(d.scale+0.01, d.inner+0.008, d.outer+0.02)
+		halfway := float32(d.maxGrowth) / 2
+		if growth := float32(d.growth); growth >= halfway {
+			mod := 1 - ((growth - halfway) / halfway)
+			sqmod := math.Sqrt(mod)
+			d.innerAlpha, d.outerAlpha = mod*sqmod, mod*sqmod
+		} else {
+			d.innerAlpha, d.outerAlpha = 1, 1
+		}
+		s.drops[n] = d
+		n++
+	}
+	s.drops = s.drops[:n]
+}
+
+// drop starts the next spare drop, at the next scheduled location if
+// there is one.
+func (s *raindropsScene) drop(voice *sound.Voice) {
+	rng := s.gctx.Rand()
+	d := s.spares[0]
+	s.spares = s.spares[1:]
+	// try not to repeat the previous drop's color
+	for i := 0; i < len(s.spares) && d.hue == s.lastHue; i++ {
+		s.spares = append(s.spares, d)
+		d = s.spares[0]
+		s.spares = s.spares[1:]
+	}
+	s.lastHue = d.hue
+	s.dropCooldown = int(rng.Int31n(int32(s.mode.maxCooldown-s.mode.minCooldown))) + s.mode.minCooldown
+	if len(s.futureDrops) > 0 {
+		d.Point = s.futureDrops[0]
+		s.futureDrops = s.futureDrops[1:]
+		// faster when there's pending action...
+		if len(s.futureDrops) > 0 {
+			s.dropCooldown = (s.dropCooldown*2 + s.mode.minCooldown + 2) / 3
+		}
+	} else {
+		d.Point = s.randomPoint()
+	}
+	growthRange := s.mode.maxGrowth - s.mode.minGrowth
+	scale := int(rng.Int31n(int32(growthRange))) + 1
+	d.maxGrowth = scale + s.mode.minGrowth
+	d.factor = float32(scale) / float32(growthRange) * 0.2
+	d.growth = 0
+	d.innerAlpha, d.outerAlpha = 1, 1
+	d.setScale(0.05+d.factor, 0.3, 1)
+	s.drops = append(s.drops, d)
+	// bigger drops make louder splashes
+	voice.PlayOctave(d.hue, d.octave, 50+int(d.factor*250))
+}
+
+func (s *raindropsScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	if km.Pressed(ebiten.KeyS) {
+		s.futureDrops = append(s.futureDrops, s.randomPoint())
+	}
+	s.cycle = (s.cycle + 1) % s.mode.cycleTime
+	if s.cycle != 0 {
+		return false, nil
+	}
+	s.grow()
+	s.dropCooldown--
+	if len(s.spares) > 0 && s.dropCooldown < 1 && int(s.gctx.Rand().Int31n(int32(len(s.spares)))) >= s.mode.dropThreshold {
+		s.drop(voice)
+	}
+	return true, nil
+}
+
+func (s *raindropsScene) Draw(screen *ebiten.Image) error {
+	s.gctx.Render(screen, func(t *ebiten.Image, scale float32) {
+		for _, d := range s.drops {
+			d.outerRing.Draw(t, d.outerAlpha, scale)
+			d.innerRing.Draw(t, d.innerAlpha, scale)
+		}
+	})
+	return nil
+}
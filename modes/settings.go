@@ -0,0 +1,201 @@
+package modes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SettingType is the type of a setting's value.
+type SettingType int
+
+const (
+	IntSetting SettingType = iota
+	FloatSetting
+)
+
+func (t SettingType) String() string {
+	switch t {
+	case IntSetting:
+		return "int"
+	case FloatSetting:
+		return "float"
+	}
+	return "unknown"
+}
+
+// Setting describes one of a mode's tunable parameters. Values of every
+// type are stored as float64.
+type Setting struct {
+	Name        string
+	Description string
+	Type        SettingType
+	Default     float64
+	Min, Max    float64
+}
+
+func intSetting(name, description string, def, min, max int) Setting {
+	return Setting{Name: name, Description: description, Type: IntSetting, Default: float64(def), Min: float64(min), Max: float64(max)}
+}
+
+func floatSetting(name, description string, def, min, max float32) Setting {
+	return Setting{Name: name, Description: description, Type: FloatSetting, Default: float64(def), Min: float64(min), Max: float64(max)}
+}
+
+// settings shared by many modes
+func cycleTimeSetting(def int) Setting {
+	return intSetting("cycleTime", "number of ticks to go by between updates", def, 1, 120)
+}
+
+func colorMultiplierSetting(def int) Setting {
+	return intSetting("colorMultiplier", "interpolation between palette colors", def, 1, 64)
+}
+
+func thicknessSetting(def int) Setting {
+	return intSetting("thickness", "line thickness", def, 1, 32)
+}
+
+// Check reports an error if v is not a valid value for the setting.
+func (s Setting) Check(v float64) error {
+	if s.Type == IntSetting && v != math.Trunc(v) {
+		return fmt.Errorf("%s: %s value must be a whole number, got %g", s.Name, s.Type, v)
+	}
+	if v < s.Min || v > s.Max {
+		return fmt.Errorf("%s: value must be between %g and %g, got %g", s.Name, s.Min, s.Max, v)
+	}
+	return nil
+}
+
+// Values holds values for a mode's settings, by setting name.
+type Values map[string]float64
+
+func (v Values) Int(name string) int {
+	return int(v[name])
+}
+
+func (v Values) Float(name string) float32 {
+	return float32(v[name])
+}
+
+// Configurable is implemented by modes which have settings.
+type Configurable interface {
+	Mode
+	// Settings describes the mode's settings. The defaults are the
+	// mode's current values.
+	Settings() []Setting
+	// Configure yields a copy of the mode using the given values,
+	// which include every setting, and are within each setting's range.
+	Configure(values Values) (Mode, error)
+}
+
+// Config holds setting values for modes, by mode name.
+type Config map[string]Values
+
+// LoadConfig reads a config from a JSON file, which maps mode names to
+// objects mapping setting names to values:
+//
+//	{"lines": {"history": 32, "thickness": 2}}
+func LoadConfig(r io.Reader) (Config, error) {
+	var raw map[string]map[string]float64
+	err := json.NewDecoder(r).Decode(&raw)
+	if err != nil {
+		return nil, err
+	}
+	c := make(Config, len(raw))
+	for mode, settings := range raw {
+		for name, v := range settings {
+			c.Set(mode, name, v)
+		}
+	}
+	return c, nil
+}
+
+// Set sets the value of a single setting.
+func (c Config) Set(mode, name string, v float64) {
+	if c[mode] == nil {
+		c[mode] = make(Values)
+	}
+	c[mode][name] = v
+}
+
+// ParseOverrides parses settings in the form mode.setting=value, separated
+// by commas, replacing any existing values for those settings.
+func (c Config) ParseOverrides(list string) error {
+	for _, o := range strings.Split(list, ",") {
+		if o == "" {
+			continue
+		}
+		eq := strings.IndexByte(o, '=')
+		dot := strings.IndexByte(o, '.')
+		if eq < 0 || dot < 0 || dot > eq {
+			return fmt.Errorf("setting %q: expected mode.setting=value", o)
+		}
+		mode, name, value := o[:dot], o[dot+1:eq], o[eq+1:]
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("setting %q: %v", o, err)
+		}
+		c.Set(mode, name, v)
+	}
+	return nil
+}
+
+// Configure replaces each mode named in the config with a copy using the
+// configured values. Settings the config doesn't mention keep their
+// current values. It is an error to configure a mode which doesn't
+// exist, or doesn't have the named settings.
+func (ml *ModeList) Configure(c Config) error {
+	// sort the names, so errors are reported consistently
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		found := false
+		for i, mode := range ml.list {
+			if mode.Name() != name {
+				continue
+			}
+			found = true
+			cm, ok := mode.(Configurable)
+			if !ok {
+				return fmt.Errorf("mode %s has no settings", name)
+			}
+			values := make(Values)
+			for _, s := range cm.Settings() {
+				values[s.Name] = s.Default
+			}
+			for setting, v := range c[name] {
+				if _, ok := values[setting]; !ok {
+					return fmt.Errorf("mode %s has no setting %q", name, setting)
+				}
+				values[setting] = v
+			}
+			for _, s := range cm.Settings() {
+				err := s.Check(values[s.Name])
+				if err != nil {
+					return fmt.Errorf("mode %s: %v", name, err)
+				}
+			}
+			configured, err := cm.Configure(values)
+			if err != nil {
+				return fmt.Errorf("mode %s: %v", name, err)
+			}
+			ml.list[i] = configured
+		}
+		if !found {
+			return fmt.Errorf("unknown mode %q", name)
+		}
+	}
+	return nil
+}
+
+// Configure modifies the default list.
+func Configure(c Config) error {
+	return defaultList.Configure(c)
+}
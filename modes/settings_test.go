@@ -0,0 +1,64 @@
+package modes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigure(t *testing.T) {
+	config, err := LoadConfig(strings.NewReader(`{"lines": {"history": 32}, "lissajous": {"deltaDelta": 0.5}}`))
+	if err != nil {
+		t.Fatalf("loading config: unexpected error: %v", err)
+	}
+	err = config.ParseOverrides("lines.thickness=5,lines.history=12")
+	if err != nil {
+		t.Fatalf("parsing overrides: unexpected error: %v", err)
+	}
+	var ml ModeList
+	ml.Add(linesModes[0])
+	ml.Add(lissajousModes[0])
+	err = ml.Configure(config)
+	if err != nil {
+		t.Fatalf("configuring: unexpected error: %v", err)
+	}
+	lines := ml.list[0].(linesMode)
+	want := linesModes[0]
+	want.history, want.thickness = 12, 5
+	if lines != want {
+		t.Errorf("lines: expected %+v, got %+v", want, lines)
+	}
+	if dd := ml.list[1].(lissajousMode).deltaDelta; dd != 0.5 {
+		t.Errorf("lissajous: expected deltaDelta 0.5, got %g", dd)
+	}
+}
+
+func TestConfigureErrors(t *testing.T) {
+	bad := []string{
+		"nosuchmode.history=3",
+		"lines.nosuchsetting=3",
+		"lines.history=0",
+		"lines.history=2.5",
+		"stringart.bandSize=300",
+		"raindrops.minGrowth=90",
+		"raindrops.dropThreshold=12",
+	}
+	for _, b := range bad {
+		config := make(Config)
+		err := config.ParseOverrides(b)
+		if err != nil {
+			t.Fatalf("%s: parsing overrides: unexpected error: %v", b, err)
+		}
+		var ml ModeList
+		ml.Add(linesModes[0])
+		ml.Add(stringArtModes[0])
+		ml.Add(raindropsModes[0])
+		if err = ml.Configure(config); err == nil {
+			t.Errorf("%s: expected error, got none", b)
+		}
+	}
+	for _, b := range []string{"lines", "lines.history", "lines.history=x"} {
+		if err := make(Config).ParseOverrides(b); err == nil {
+			t.Errorf("%s: expected parse error, got none", b)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package modes
+
+import (
+	"math/rand"
+
+	math "github.com/chewxy/math32"
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// spiralMode is one of the internal modes based on lines, drawing
+// spiral arms from a center out to bouncing points.
+type spiralMode struct {
+	name        string
+	description string
+	cycleTime   int // number of ticks to go by between updates
+	arms        int
+	colorPoints int // points per arm, per palette color
+	history     int // number of trailing copies of each arm to draw
+	drift       bool
+}
+
+const spiralCycleTime = 1
+
+var spiralModes = []spiralMode{
+	{name: "spiral1", description: "spiraling arms bounce around the screen", cycleTime: spiralCycleTime, arms: 3, colorPoints: 8, history: 16},
+	{name: "spiral2", description: "spiraling arms bounce around a drifting center", cycleTime: spiralCycleTime, arms: 3, colorPoints: 8, history: 16, drift: true},
+}
+
+func init() {
+	for _, mode := range spiralModes {
+		defaultList.Add(mode)
+	}
+}
+
+func (m spiralMode) Name() string {
+	return m.name
+}
+
+func (m spiralMode) Description() string {
+	return m.description
+}
+
+func (m spiralMode) Settings() []Setting {
+	return []Setting{
+		cycleTimeSetting(m.cycleTime),
+		intSetting("arms", "number of spiral arms", m.arms, 1, 16),
+		intSetting("colorPoints", "points per arm, per palette color", m.colorPoints, 1, 64),
+		intSetting("history", "number of trailing copies of each arm to draw", m.history, 1, 256),
+	}
+}
+
+func (m spiralMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	m.arms = v.Int("arms")
+	m.colorPoints = v.Int("colorPoints")
+	m.history = v.Int("history")
+	return m, nil
+}
+
+func (m spiralMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
+	return newSpiralScene(m, gctx, detail, p)
+}
+
+type spiralScene struct {
+	palette *g.Palette
+	gctx    *g.Context
+	mode    spiralMode
+	detail  int
+	cycle   int
+	bounds  g.Region
+	center  g.MovingPoint
+	spirals []*g.Spiral
+}
+
+func newSpiralScene(m spiralMode, gctx *g.Context, detail int, p *g.Palette) (*spiralScene, error) {
+	sc := &spiralScene{mode: m, gctx: gctx, detail: detail}
+	_, _, _, cx, cy := gctx.Centered()
+	sc.bounds = g.Region{
+		Min: g.Point{X: -1 - cx, Y: -1 - cy},
+		Max: g.Point{X: 1 + cx, Y: 1 + cy},
+	}
+	err := sc.Reset(detail, p)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (s *spiralScene) Mode() Mode {
+	return s.mode
+}
+
+func (s *spiralScene) Reset(detail int, p *g.Palette) error {
+	_ = s.Hide()
+	s.detail = detail
+	s.palette = p
+	err := s.Display()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// randomVelocity yields a random velocity of up to speed in each
+// direction.
+func randomVelocity(rng *rand.Rand, speed float32) g.Vec {
+	return g.Vec{X: (rng.Float32()*2 - 1) * speed, Y: (rng.Float32()*2 - 1) * speed}
+}
+
+func (s *spiralScene) Display() error {
+	rng := s.gctx.Rand()
+	// the center drifts, if it does, in the middle half of the screen
+	s.center = g.MovingPoint{
+		Bounds: g.Region{
+			Min: g.Point{X: s.bounds.Min.X / 2, Y: s.bounds.Min.Y / 2},
+			Max: g.Point{X: s.bounds.Max.X / 2, Y: s.bounds.Max.Y / 2},
+		},
+		Velocity: randomVelocity(rng, 0.004),
+	}
+	min, max := s.bounds.Min, s.bounds.Max
+	s.spirals = make([]*g.Spiral, s.mode.arms)
+	for i := range s.spirals {
+		sp := s.gctx.NewSpiral(s.mode.history, 1, s.mode.colorPoints*s.palette.Length, s.palette, 1, i*s.palette.Length/s.mode.arms)
+		sp.Center = s.center
+		sp.Target = g.MovingPoint{
+			Loc: g.Point{
+				X: min.X + rng.Float32()*(max.X-min.X),
+				Y: min.Y + rng.Float32()*(max.Y-min.Y),
+			},
+			Velocity: randomVelocity(rng, 0.02),
+			Bounds:   s.bounds,
+		}
+		sp.Theta = 5 * math.Pi
+		s.spirals[i] = sp
+	}
+	// fill in the history
+	for i := 0; i < s.mode.history; i++ {
+		s.update(nil)
+	}
+	return nil
+}
+
+func (s *spiralScene) Hide() error {
+	s.spirals = nil
+	return nil
+}
+
+// update moves the center, if it drifts, and every arm, playing a note
+// for the last arm to bounce.
+func (s *spiralScene) update(voice *sound.Voice) {
+	if s.mode.drift {
+		s.center.Update()
+	}
+	bounced, bounceNote := -1, 0
+	for i, sp := range s.spirals {
+		sp.Center.Loc = s.center.Loc
+		if b, note, _ := sp.Update(); b {
+			bounced, bounceNote = i, note
+		}
+	}
+	if bounced >= 0 {
+		voice.PlayOctave(bounceNote, bounced, 70)
+	}
+}
+
+func (s *spiralScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	s.cycle = (s.cycle + 1) % s.mode.cycleTime
+	if s.cycle != 0 {
+		return false, nil
+	}
+	s.update(voice)
+	return true, nil
+}
+
+func (s *spiralScene) Draw(screen *ebiten.Image) error {
+	s.gctx.Render(screen, func(t *ebiten.Image, scale float32) {
+		for _, sp := range s.spirals {
+			sp.Draw(t, scale)
+		}
+	})
+	return nil
+}
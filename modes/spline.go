@@ -0,0 +1,196 @@
+package modes
+
+import (
+	math "github.com/chewxy/math32"
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// splineMode is one of the internal modes based on lines, drawing a
+// curve whose control points bounce around the screen.
+type splineMode struct {
+	name            string
+	description     string
+	cycleTime       int // number of ticks to go by between updates
+	colorMultiplier int // interpolation between palette colors
+	history         int // number of trailing curves to draw
+	thickness       int
+	catmullRom      bool // pass through the points, rather than using them as Bezier controls
+}
+
+const (
+	splineCycleTime = 1
+	splinePoints    = 4
+)
+
+var splineModes = []splineMode{
+	{name: "spline", description: "the control points for a Bezier spline bounce around the screen", cycleTime: splineCycleTime, colorMultiplier: 8, history: 16, thickness: 3},
+	{name: "splineCatmullRom", description: "a Catmull-Rom spline passes through points bouncing around the screen", cycleTime: splineCycleTime, colorMultiplier: 8, history: 16, thickness: 3, catmullRom: true},
+}
+
+func init() {
+	for _, mode := range splineModes {
+		defaultList.Add(mode)
+	}
+}
+
+func (m splineMode) Name() string {
+	return m.name
+}
+
+func (m splineMode) Description() string {
+	return m.description
+}
+
+func (m splineMode) Settings() []Setting {
+	return []Setting{
+		cycleTimeSetting(m.cycleTime),
+		colorMultiplierSetting(m.colorMultiplier),
+		intSetting("history", "number of trailing curves to draw", m.history, 1, 256),
+		thicknessSetting(m.thickness),
+	}
+}
+
+func (m splineMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	m.colorMultiplier = v.Int("colorMultiplier")
+	m.history = v.Int("history")
+	m.thickness = v.Int("thickness")
+	return m, nil
+}
+
+func (m splineMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
+	return newSplineScene(m, gctx, detail, p)
+}
+
+type splineScene struct {
+	palette   *g.Palette
+	gctx      *g.Context
+	mode      splineMode
+	detail    int
+	cycle     int
+	bounds    g.Region
+	points    [splinePoints]g.MovingPoint
+	locs      [splinePoints]g.Point
+	pl        []*g.PolyLine
+	nextColor g.Paint
+	colorSkip int
+}
+
+func newSplineScene(m splineMode, gctx *g.Context, detail int, p *g.Palette) (*splineScene, error) {
+	sc := &splineScene{mode: m, gctx: gctx, detail: detail}
+	_, _, _, cx, cy := gctx.Centered()
+	sc.bounds = g.Region{
+		Min: g.Point{X: -1 - cx, Y: -1 - cy},
+		Max: g.Point{X: 1 + cx, Y: 1 + cy},
+	}
+	err := sc.Reset(detail, p)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (s *splineScene) Mode() Mode {
+	return s.mode
+}
+
+func (s *splineScene) Reset(detail int, p *g.Palette) error {
+	_ = s.Hide()
+	s.detail = detail
+	s.palette = p.Interpolate(s.mode.colorMultiplier)
+	err := s.Display()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *splineScene) Display() error {
+	rng := s.gctx.Rand()
+	min, max := s.bounds.Min, s.bounds.Max
+	for i := range s.points {
+		s.points[i] = g.MovingPoint{
+			Loc: g.Point{
+				X: min.X + rng.Float32()*(max.X-min.X),
+				Y: min.Y + rng.Float32()*(max.Y-min.Y),
+			},
+			Velocity: randomVelocity(rng, 0.02),
+			Bounds:   s.bounds,
+		}
+	}
+	s.nextColor = 0
+	s.colorSkip = s.palette.Length / (s.mode.history + 2)
+	s.pl = make([]*g.PolyLine, s.mode.history)
+	for i := range s.pl {
+		pl := s.gctx.NewPolyline(s.mode.thickness, 1, s.palette)
+		pl.Joined = true
+		s.pl[i] = pl
+		s.compute(pl)
+		s.move()
+	}
+	return nil
+}
+
+func (s *splineScene) Hide() error {
+	s.pl = nil
+	return nil
+}
+
+// compute sets pl to the curve for the current points, starting at the
+// next color.
+func (s *splineScene) compute(pl *g.PolyLine) {
+	for i := range s.points {
+		s.locs[i] = s.points[i].Loc
+	}
+	segments := s.palette.Length
+	if s.mode.catmullRom {
+		pl.CatmullRom(s.locs[:], segments/(splinePoints-1))
+	} else {
+		pl.Bezier(s.locs[0], s.locs[1], s.locs[2], s.locs[3], segments)
+	}
+	color := s.nextColor
+	for i := range pl.Points {
+		pl.Points[i].P = color
+		color = s.palette.Inc(color, 1)
+	}
+	s.nextColor = s.palette.Inc(s.nextColor, s.colorSkip+1)
+}
+
+// move moves every point, reporting whether either end of the curve
+// bounced.
+func (s *splineScene) move() (bounced bool) {
+	for i := range s.points {
+		if s.points[i].Update() && (i == 0 || i == splinePoints-1) {
+			bounced = true
+		}
+	}
+	return bounced
+}
+
+func (s *splineScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	s.cycle = (s.cycle + 1) % s.mode.cycleTime
+	if s.cycle != 0 {
+		return false, nil
+	}
+	// reuse the oldest curve as the newest
+	line := s.pl[0]
+	copy(s.pl, s.pl[1:])
+	s.pl[len(s.pl)-1] = line
+	s.compute(line)
+	if s.move() {
+		voice.Play(int(s.nextColor)/s.mode.colorMultiplier, 70)
+	}
+	return true, nil
+}
+
+func (s *splineScene) Draw(screen *ebiten.Image) error {
+	s.gctx.Render(screen, func(t *ebiten.Image, scale float32) {
+		for i, pl := range s.pl {
+			pl.Draw(t, math.Sqrt(float32(i+1)/float32(len(s.pl))), scale)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,334 @@
+package modes
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// stringArtMode is one of the internal modes based on lines, where line
+// endpoints wander between interesting points on the edges of the
+// screen, leaving trails.
+type stringArtMode struct {
+	cycleTime       int // number of ticks to go by between updates
+	colorMultiplier int // interpolation between palette colors
+	strings         int // number of wandering lines
+	history         int // number of segments kept at full alpha
+	bandSize        int // number of segments per PolyLine
+	fadeBands       int // number of bands fading out past the history limit
+	thickness       int
+}
+
+const stringArtCycleTime = 1
+
+var stringArtModes = []stringArtMode{
+	{cycleTime: stringArtCycleTime, colorMultiplier: 8, strings: 3, history: 288, bandSize: 24, fadeBands: 3, thickness: 2},
+}
+
+func init() {
+	for _, mode := range stringArtModes {
+		defaultList.Add(mode)
+	}
+}
+
+func (m stringArtMode) Name() string {
+	return "stringart"
+}
+
+func (m stringArtMode) Description() string {
+	return "lines wander around the screen, creating patterns in their trails"
+}
+
+func (m stringArtMode) Settings() []Setting {
+	return []Setting{
+		cycleTimeSetting(m.cycleTime),
+		colorMultiplierSetting(m.colorMultiplier),
+		intSetting("strings", "number of wandering lines", m.strings, 1, 32),
+		intSetting("history", "number of segments kept at full alpha", m.history, 1, 4096),
+		intSetting("bandSize", "number of segments per band", m.bandSize, 1, 256),
+		intSetting("fadeBands", "number of bands fading out past the history limit", m.fadeBands, 0, 64),
+		thicknessSetting(m.thickness),
+	}
+}
+
+func (m stringArtMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	m.colorMultiplier = v.Int("colorMultiplier")
+	m.strings = v.Int("strings")
+	m.history = v.Int("history")
+	m.bandSize = v.Int("bandSize")
+	m.fadeBands = v.Int("fadeBands")
+	m.thickness = v.Int("thickness")
+	// every string adds a segment to the current band on each update
+	if m.bandSize < m.strings {
+		return nil, fmt.Errorf("bandSize (%d) must be at least strings (%d)", m.bandSize, m.strings)
+	}
+	if m.history < m.bandSize {
+		return nil, fmt.Errorf("history (%d) must be at least bandSize (%d)", m.history, m.bandSize)
+	}
+	return m, nil
+}
+
+func (m stringArtMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
+	return newStringArtScene(m, gctx, detail, p)
+}
+
+// stringArtInteresting are sets of interesting points, as fractions of
+// the screen's width and height.
+var stringArtInteresting = [][]g.Point{
+	{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}},
+	{{X: 0.5, Y: 0}, {X: 0, Y: 0.5}, {X: 0.5, Y: 1}, {X: 1, Y: 0.5}},
+	{{X: 1. / 3, Y: 0}, {X: 1, Y: 1. / 3}, {X: 2. / 3, Y: 1}, {X: 0, Y: 2. / 3}},
+	{
+		{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1},
+		{X: 0.5, Y: 0}, {X: 0, Y: 0.5}, {X: 0.5, Y: 1}, {X: 1, Y: 0.5},
+	},
+	{{X: 0.75, Y: 0}, {X: 1, Y: 0.75}, {X: 0.25, Y: 1}, {X: 0, Y: 0.25}},
+	{
+		{X: 0.25, Y: 0}, {X: 0.75, Y: 0}, {X: 1, Y: 0.25}, {X: 1, Y: 0.75},
+		{X: 0.25, Y: 1}, {X: 0.75, Y: 1}, {X: 0, Y: 0.25}, {X: 0, Y: 0.75},
+	},
+}
+
+// isCorner reports whether a point, in screen fractions, is a corner.
+func isCorner(p g.Point) bool {
+	return (p.X == 0 || p.X == 1) && (p.Y == 0 || p.Y == 1)
+}
+
+// isBoring reports whether moving towards p from a line between a and
+// b would be boring, because it's on one of the ends, or on the same
+// edge as both of them.
+func isBoring(a, b, p g.Point) bool {
+	return (p.X == a.X && p.X == b.X) || (p.Y == a.Y && p.Y == b.Y) || p == a || p == b
+}
+
+// stringer is one wandering line. Normally, its moving end moves
+// towards the target, while the other end stays put. When the moving
+// end arrives, the fixed end starts moving, and a new target is picked.
+// If moveBoth is set, the fixed end instead moves to where the moving
+// end started, so the line sweeps across.
+//
+// Points are in screen fractions.
+type stringer struct {
+	index      int
+	set        int // which set of interesting points to use
+	pick       int // most recently picked point in that set
+	from, to   [2]g.Point
+	target     g.Point
+	step       int
+	steps      int
+	moveBoth   bool
+	corners    bool // the last target came from the corners
+	nextColor  g.Paint
+	current    [2]g.Point
+	prevTarget g.Point
+}
+
+type stringArtScene struct {
+	palette       *g.Palette
+	gctx          *g.Context
+	mode          stringArtMode
+	detail        int
+	cycle         int
+	width, height float32
+	stringers     []stringer
+	bands         []*g.PolyLine
+	bandSegments  int // segments in the newest band
+	quiet         bool
+}
+
+func newStringArtScene(m stringArtMode, gctx *g.Context, detail int, p *g.Palette) (*stringArtScene, error) {
+	sc := &stringArtScene{mode: m, gctx: gctx, detail: detail}
+	_, _, _, cx, cy := gctx.Centered()
+	sc.width, sc.height = 1+cx, 1+cy
+	err := sc.Reset(detail, p)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (s *stringArtScene) Mode() Mode {
+	return s.mode
+}
+
+func (s *stringArtScene) Reset(detail int, p *g.Palette) error {
+	_ = s.Hide()
+	s.detail = detail
+	s.palette = p.Interpolate(s.mode.colorMultiplier)
+	err := s.Display()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *stringArtScene) Display() error {
+	s.bands = make([]*g.PolyLine, s.mode.history/s.mode.bandSize+s.mode.fadeBands)
+	for i := range s.bands {
+		pl := s.gctx.NewPolyline(s.mode.thickness, 1, s.palette)
+		pl.Joined = false
+		pl.Blend = false
+		s.bands[i] = pl
+	}
+	s.bandSegments = 0
+	s.stringers = make([]stringer, s.mode.strings)
+	stepsBase := s.palette.Length * 3 / 4
+	for i := range s.stringers {
+		st := &s.stringers[i]
+		st.index = i
+		st.set = i % len(stringArtInteresting)
+		st.steps = stepsBase + i*2
+		st.nextColor = s.palette.Paint(i * s.palette.Length / s.mode.strings)
+		ip := stringArtInteresting[st.set]
+		st.pick = int(s.gctx.Rand().Int31n(int32(len(ip))))
+		st.from[0] = ip[st.pick]
+		st.pick = (st.pick + 1) % len(ip)
+		st.from[1] = ip[st.pick]
+		st.to = st.from
+		st.current = st.from
+		s.retarget(st, st.set)
+	}
+	// fill in the history
+	s.quiet = true
+	for i := 0; i < s.mode.history/s.mode.strings; i++ {
+		s.update(nil)
+	}
+	s.quiet = false
+	return nil
+}
+
+func (s *stringArtScene) Hide() error {
+	s.bands = nil
+	s.stringers = nil
+	return nil
+}
+
+// retarget picks a new target for st from the given set of interesting
+// points, avoiding boring ones if possible.
+func (s *stringArtScene) retarget(st *stringer, set int) {
+	ip := stringArtInteresting[set%len(stringArtInteresting)]
+	a, b := st.to[0], st.to[1]
+	pick := (st.pick + 1) % len(ip)
+	for i := 0; i < len(ip) && isBoring(a, b, ip[pick]); i++ {
+		pick = (pick + 1) % len(ip)
+	}
+	st.pick = pick
+	st.prevTarget = st.target
+	st.target = ip[pick]
+	st.step = 0
+	st.from = st.to
+	lastMoveBoth := st.moveBoth
+	st.moveBoth = true
+	switch {
+	case st.target == st.prevTarget || st.target == b:
+		// if we picked the same points somehow, change the behavior
+		st.moveBoth = !lastMoveBoth
+	case isCorner(a) && isCorner(st.target) && (a.X == st.target.X || a.Y == st.target.Y):
+		// sometimes draw straight rather than curved along an edge
+		st.moveBoth = s.gctx.Rand().Int31n(2) == 0
+	}
+	st.to[0] = st.target
+	if st.moveBoth {
+		st.to[1] = a
+	} else {
+		st.to[1] = b
+	}
+}
+
+// arrive handles a stringer's moving end reaching its target.
+func (s *stringArtScene) arrive(voice *sound.Voice, st *stringer) {
+	rng := s.gctx.Rand()
+	if st.moveBoth {
+		// 50-50 chance of which end we consider the new "moving" end
+		if rng.Int31n(2) == 0 {
+			st.to[0], st.to[1] = st.to[1], st.to[0]
+		}
+	} else {
+		// the fixed end starts moving, and the end which just arrived
+		// stays put
+		st.to[0], st.to[1] = st.to[1], st.to[0]
+	}
+	set := st.set
+	// sometimes head for a corner, but never twice in a row
+	if rng.Int31n(3) == 0 {
+		if !st.corners {
+			set = 0
+		}
+		st.corners = !st.corners
+	}
+	s.retarget(st, set)
+	if !s.quiet {
+		voice.PlayOctave(int(st.nextColor)/s.mode.colorMultiplier, st.index, 60)
+	}
+	if st.index > 1 && rng.Int31n(6) == 0 {
+		st.set = (st.set + 1) % len(stringArtInteresting)
+	}
+}
+
+// screen converts a point in screen fractions to drawing coordinates.
+func (s *stringArtScene) screen(p g.Point) g.Point {
+	return g.Point{X: (p.X*2 - 1) * s.width, Y: (p.Y*2 - 1) * s.height}
+}
+
+// update adds a segment for each stringer, then moves them along.
+func (s *stringArtScene) update(voice *sound.Voice) {
+	if s.bandSegments+len(s.stringers) > s.mode.bandSize {
+		// reuse the oldest band as the newest
+		band := s.bands[0]
+		copy(s.bands, s.bands[1:])
+		s.bands[len(s.bands)-1] = band
+		band.Reset()
+		s.bandSegments = 0
+	}
+	band := s.bands[len(s.bands)-1]
+	for i := range s.stringers {
+		st := &s.stringers[i]
+		a, b := s.screen(st.current[0]), s.screen(st.current[1])
+		band.Add(a.X, a.Y, st.nextColor)
+		band.Add(b.X, b.Y, st.nextColor)
+		st.nextColor = s.palette.Inc(st.nextColor, 1)
+		st.step++
+		t := float32(st.step) / float32(st.steps)
+		for j := range st.current {
+			st.current[j] = g.Point{
+				X: st.from[j].X + (st.to[j].X-st.from[j].X)*t,
+				Y: st.from[j].Y + (st.to[j].Y-st.from[j].Y)*t,
+			}
+		}
+		if st.step >= st.steps {
+			st.current = st.to
+			s.arrive(voice, st)
+		}
+	}
+	s.bandSegments += len(s.stringers)
+}
+
+func (s *stringArtScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	s.cycle = (s.cycle + 1) % s.mode.cycleTime
+	if s.cycle != 0 {
+		return false, nil
+	}
+	s.update(voice)
+	return true, nil
+}
+
+func (s *stringArtScene) Draw(screen *ebiten.Image) error {
+	s.gctx.Render(screen, func(t *ebiten.Image, scale float32) {
+		for i, band := range s.bands {
+			if band.Length() < 2 {
+				continue
+			}
+			// bands past the history limit fade out
+			alpha := float32(1)
+			if i < s.mode.fadeBands {
+				alpha = float32(i+1) / float32(s.mode.fadeBands+1)
+			}
+			band.Draw(t, alpha, scale)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,93 @@
+package modes
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// Transition blends from an outgoing scene to an incoming one over a
+// fixed number of ticks. Both scenes keep running while it does, each
+// drawing to an offscreen image; only the incoming scene gets input.
+type Transition struct {
+	gctx     *g.Context
+	tt       g.TransitionType
+	from, to Scene
+	ticks    int
+	elapsed  int
+	fromImg  *ebiten.Image
+	toImg    *ebiten.Image
+}
+
+// NewTransition creates a transition from one scene to another, which
+// takes the given number of ticks.
+func NewTransition(gctx *g.Context, tt g.TransitionType, from, to Scene, ticks int) (*Transition, error) {
+	if ticks < 1 {
+		ticks = 1
+	}
+	t := &Transition{gctx: gctx, tt: tt, from: from, to: to, ticks: ticks}
+	var err error
+	t.fromImg, err = gctx.NewScreenImage()
+	if err != nil {
+		return nil, err
+	}
+	t.toImg, err = gctx.NewScreenImage()
+	if err != nil {
+		t.fromImg.Dispose()
+		return nil, err
+	}
+	return t, nil
+}
+
+// Scene yields the incoming scene.
+func (t *Transition) Scene() Scene {
+	return t.to
+}
+
+// Done reports whether the transition has finished.
+func (t *Transition) Done() bool {
+	return t.elapsed >= t.ticks
+}
+
+// Tick advances both scenes, and the transition. The outgoing scene
+// doesn't get input or sound.
+func (t *Transition) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	_, err := t.from.Tick(nil, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	if t.elapsed < t.ticks {
+		t.elapsed++
+	}
+	return t.to.Tick(voice, km, pointers)
+}
+
+// Draw draws both scenes offscreen, then blends them onto screen.
+func (t *Transition) Draw(screen *ebiten.Image) error {
+	for _, img := range []*ebiten.Image{t.fromImg, t.toImg} {
+		err := t.gctx.ClearScreenImage(img)
+		if err != nil {
+			return err
+		}
+	}
+	err := t.from.Draw(t.fromImg)
+	if err != nil {
+		return err
+	}
+	err = t.to.Draw(t.toImg)
+	if err != nil {
+		return err
+	}
+	t.gctx.DrawTransition(screen, t.fromImg, t.toImg, t.tt, float32(t.elapsed)/float32(t.ticks))
+	return nil
+}
+
+// Finish hides the outgoing scene, and releases the offscreen images.
+// It can be called before the transition is done, to cut straight to
+// the incoming scene.
+func (t *Transition) Finish() error {
+	t.fromImg.Dispose()
+	t.toImg.Dispose()
+	return t.from.Hide()
+}
@@ -0,0 +1,88 @@
+package modes
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// recordingScene is a Scene which records how it's been used.
+type recordingScene struct {
+	ticks     int
+	withInput int // ticks which got keys or pointers
+	withVoice int // ticks which got a voice
+	displayed bool
+}
+
+func (s *recordingScene) Mode() Mode {
+	return nil
+}
+
+func (s *recordingScene) Display() error {
+	s.displayed = true
+	return nil
+}
+
+func (s *recordingScene) Hide() error {
+	s.displayed = false
+	return nil
+}
+
+func (s *recordingScene) Reset(detail int, p *g.Palette) error {
+	return s.Display()
+}
+
+func (s *recordingScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	s.ticks++
+	if km != nil || pointers != nil {
+		s.withInput++
+	}
+	if voice != nil {
+		s.withVoice++
+	}
+	return true, nil
+}
+
+func (s *recordingScene) Draw(screen *ebiten.Image) error {
+	return nil
+}
+
+func TestTransition(t *testing.T) {
+	c := g.NewContext(1280, 960, false)
+	from, to := &recordingScene{displayed: true}, &recordingScene{displayed: true}
+	tr, err := NewTransition(c, g.Crossfade, from, to, 5)
+	if err != nil {
+		t.Fatalf("creating transition: %v", err)
+	}
+	km := keys.NewMap(ebiten.KeyA)
+	pointers := keys.Pointers{{ID: keys.MouseID, State: keys.PRESS}}
+	for i := 0; i < 5; i++ {
+		if tr.Done() {
+			t.Fatalf("transition done after %d of 5 ticks", i)
+		}
+		if _, err := tr.Tick(nil, km, pointers); err != nil {
+			t.Fatalf("tick %d: %v", i, err)
+		}
+	}
+	if !tr.Done() {
+		t.Fatalf("transition not done after 5 ticks")
+	}
+	if from.ticks != 5 || to.ticks != 5 {
+		t.Errorf("expected both scenes to tick 5 times, got %d and %d", from.ticks, to.ticks)
+	}
+	if from.withInput != 0 || to.withInput != 5 {
+		t.Errorf("expected only the incoming scene to get input, got %d and %d ticks with input", from.withInput, to.withInput)
+	}
+	if tr.Scene() != Scene(to) {
+		t.Errorf("expected Scene to yield the incoming scene")
+	}
+	if err := tr.Finish(); err != nil {
+		t.Fatalf("finishing transition: %v", err)
+	}
+	if from.displayed || !to.displayed {
+		t.Errorf("expected Finish to hide only the outgoing scene, got displayed %t and %t", from.displayed, to.displayed)
+	}
+}
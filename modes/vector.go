@@ -2,7 +2,6 @@ package modes
 
 import (
 	"fmt"
-	"math/rand"
 
 	math "github.com/chewxy/math32"
 	"github.com/hajimehoshi/ebiten"
@@ -43,6 +42,15 @@ func (m vectorMode) Description() string {
 	return fmt.Sprintf("vector: %s", m.name)
 }
 
+func (m vectorMode) Settings() []Setting {
+	return []Setting{cycleTimeSetting(m.cycleTime)}
+}
+
+func (m vectorMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	return m, nil
+}
+
 func (m vectorMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
 	return newVectorScene(m, gctx, detail, p)
 }
@@ -128,14 +136,15 @@ func simpleDemoInit(s *vectorScene) {
 }
 
 func simpleDemo(s *vectorScene, km keys.Map) string {
+	rng := s.gctx.Rand()
 	b := &s.bouncers[0]
 	sin, cos := math.Sincos(b.ship.Theta)
 	if s.keysReady {
 		if km.Down(ebiten.KeyW, ebiten.KeyUp) {
 			b.pt.Velocity.X += cos * .0001
 			b.pt.Velocity.Y += sin * .0001
-			dx := -(0.0625 + (rand.Float32() / 8))
-			dy := (rand.Float32() - 0.5) / 8
+			dx := -(0.0625 + (rng.Float32() / 8))
+			dy := (rng.Float32() - 0.5) / 8
 			aDy := math.Abs(dy)
 			paint := g.Paint(b.pOffset + 1)
 			if aDy > 0.05 {
@@ -156,7 +165,7 @@ func simpleDemo(s *vectorScene, km keys.Map) string {
 				State: g.ParticleState{
 					ParticlePos: g.ParticlePos{X: -0.01},
 					P:           paint,
-					Scale:       rand.Float32()/16 + 0.125,
+					Scale:       rng.Float32()/16 + 0.125,
 				},
 				Delta: g.ParticlePos{X: dx, Y: dy, Theta: dTheta},
 			}
@@ -232,6 +241,7 @@ func (s *vectorScene) Mode() Mode {
 
 func (s *vectorScene) Reset(detail int, p *g.Palette) error {
 	_ = s.Hide()
+	s.detail = detail
 	s.palette = p.Interpolate(weaveInterpolate)
 	err := s.Display()
 	if err != nil {
@@ -263,7 +273,7 @@ func (s *vectorScene) Hide() error {
 	return nil
 }
 
-func (s *vectorScene) Tick(voice *sound.Voice, km keys.Map) (bool, error) {
+func (s *vectorScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
 	s.cycle = (s.cycle + 1) % s.mode.cycleTime
 	s.t0++
 	if s.cycle != 0 {
@@ -0,0 +1,295 @@
+package modes
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"seebs.net/modus/g"
+	"seebs.net/modus/keys"
+	"seebs.net/modus/sound"
+)
+
+// wanderingMode is one of the internal modes based on hexes which pull
+// nearby hexes towards their own color
+type wanderingMode struct {
+	cycleTime       int // number of ticks to go by between updates
+	colorMultiplier int // interpolation between palette colors
+	ants            int
+	metaCycle       int // every metaCycle moves, an ant moves towards the next ant
+}
+
+const wanderingCycleTime = 4
+
+var wanderingModes = []wanderingMode{
+	{cycleTime: wanderingCycleTime, colorMultiplier: 6, ants: 6, metaCycle: 12},
+}
+
+func init() {
+	for _, mode := range wanderingModes {
+		defaultList.Add(mode)
+	}
+}
+
+func (m wanderingMode) Name() string {
+	return "wandering"
+}
+
+func (m wanderingMode) Description() string {
+	return "colored hexes wander, changing things towards their own color"
+}
+
+func (m wanderingMode) Settings() []Setting {
+	return []Setting{
+		cycleTimeSetting(m.cycleTime),
+		colorMultiplierSetting(m.colorMultiplier),
+		intSetting("ants", "number of wandering ants", m.ants, 1, 64),
+		intSetting("metaCycle", "moves between an ant heading towards the next ant", m.metaCycle, 1, 600),
+	}
+}
+
+func (m wanderingMode) Configure(v Values) (Mode, error) {
+	m.cycleTime = v.Int("cycleTime")
+	m.colorMultiplier = v.Int("colorMultiplier")
+	m.ants = v.Int("ants")
+	m.metaCycle = v.Int("metaCycle")
+	return m, nil
+}
+
+func (m wanderingMode) New(gctx *g.Context, detail int, p *g.Palette) (Scene, error) {
+	return newWanderingScene(m, gctx, detail, p)
+}
+
+type wanderingAnt struct {
+	g.ILoc
+	dir g.HexDir
+	P   g.Paint
+	c   *g.FloatingHexCell
+}
+
+func (a *wanderingAnt) apply() {
+	if a.c != nil {
+		a.c.P = a.P
+		*a.c.X() = float32(a.X)
+		*a.c.Y() = float32(a.Y)
+	}
+}
+
+// wanderingSplash is a pending splash of color around a hex.
+type wanderingSplash struct {
+	g.ILoc
+	P        g.Paint
+	cooldown int
+}
+
+type wanderingScene struct {
+	palette      *g.Palette
+	gctx         *g.Context
+	mode         wanderingMode
+	detail       int
+	gr           *g.HexGrid
+	cycle        int
+	ants         []wanderingAnt
+	nextAnt      int
+	metaCooldown int
+	fadeColumn   int
+	splashes     []wanderingSplash
+	soundToggle  bool
+	makeSound    bool
+	sound        int
+	octave       int
+}
+
+func newWanderingScene(m wanderingMode, gctx *g.Context, detail int, p *g.Palette) (*wanderingScene, error) {
+	sc := &wanderingScene{mode: m, gctx: gctx, detail: detail, ants: make([]wanderingAnt, m.ants)}
+	err := sc.Reset(detail, p)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (s *wanderingScene) Mode() Mode {
+	return s.mode
+}
+
+func (s *wanderingScene) Reset(detail int, p *g.Palette) error {
+	_ = s.Hide()
+	s.detail = detail
+	s.palette = p.Interpolate(s.mode.colorMultiplier)
+	err := s.Display()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *wanderingScene) Display() error {
+	s.gr = s.gctx.NewHexGrid(s.detail, 1, s.palette)
+	s.gr.Iterate(func(generic g.Grid, l g.ILoc, n int, c *g.Cell) {
+		c.P = s.palette.Paint(int(s.gctx.Rand().Int31n(int32(s.palette.Length))))
+		c.Alpha = 0.75
+	})
+	for i := range s.ants {
+		a := &s.ants[i]
+		a.c = s.gr.NewExtraCell().(*g.FloatingHexCell)
+		a.ILoc = s.gr.NewLoc()
+		a.dir = s.gr.NewDir()
+		a.P = s.palette.Paint((i + 1) * s.mode.colorMultiplier)
+		s.gr.At(a.ILoc).P = a.P
+		a.apply()
+	}
+	s.nextAnt = 0
+	s.metaCooldown = s.mode.metaCycle
+	s.fadeColumn = 0
+	s.splashes = s.splashes[:0]
+	return nil
+}
+
+func (s *wanderingScene) Hide() error {
+	for i := range s.ants {
+		s.ants[i].c = nil
+	}
+	s.gr = nil
+	return nil
+}
+
+// antAt yields the ant at l, if any.
+func (s *wanderingScene) antAt(l g.ILoc) *wanderingAnt {
+	for i := range s.ants {
+		if s.ants[i].ILoc == l {
+			return &s.ants[i]
+		}
+	}
+	return nil
+}
+
+// hexDistance is the distance between two locations in axial coordinates,
+// ignoring wrapping.
+func hexDistance(a, b g.ILoc) int {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	dz := dx + dy
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	if dz < 0 {
+		dz = -dz
+	}
+	return (dx + dy + dz) / 2
+}
+
+// hexToward yields the direction from a which most reduces the distance
+// to b, and false if a and b are the same location.
+func hexToward(a, b g.ILoc) (g.HexDir, bool) {
+	best, bestDist := g.HexDir(0), hexDistance(a, b)
+	if bestDist == 0 {
+		return best, false
+	}
+	for d := g.HexDir(0); d < 6; d++ {
+		v := d.IVec()
+		dist := hexDistance(g.ILoc{X: a.X + v.X, Y: a.Y + v.Y}, b)
+		if dist < bestDist {
+			best, bestDist = d, dist
+		}
+	}
+	return best, true
+}
+
+// trail pulls a hex the ant has just passed towards the ant's color.
+func (s *wanderingScene) trail(a *wanderingAnt, d g.HexDir) {
+	l, _ := s.gr.Add(a.ILoc, d.IVec())
+	c := s.gr.At(l)
+	c.IncAlpha(0.1)
+	c.P = s.palette.Toward(c.P, a.P, 1)
+	if other := s.antAt(l); other != nil {
+		s.makeSound = true
+		s.sound = int(other.P) / s.mode.colorMultiplier
+		s.octave = 1
+	}
+}
+
+func (s *wanderingScene) Tick(voice *sound.Voice, km keys.Map, pointers keys.Pointers) (bool, error) {
+	if km.Pressed(ebiten.KeyS) {
+		l := s.gr.NewLoc()
+		s.splashes = append(s.splashes, wanderingSplash{ILoc: l, P: s.gr.At(l).P, cooldown: 1})
+	}
+	s.cycle = (s.cycle + 1) % s.mode.cycleTime
+	if s.cycle != 0 {
+		return false, nil
+	}
+	idx := s.nextAnt
+	a := &s.ants[idx]
+	s.nextAnt = (s.nextAnt + 1) % len(s.ants)
+	s.gr.IncP(a.ILoc, -1)
+	s.metaCooldown--
+	// every so often, move towards the next ant
+	if s.metaCooldown < 1 {
+		if dir, ok := hexToward(a.ILoc, s.ants[s.nextAnt].ILoc); ok {
+			a.dir = dir
+		}
+		s.metaCooldown = s.mode.metaCycle
+	} else {
+		chance := s.gctx.Rand().Int31n(100)
+		switch {
+		case chance >= 90:
+			a.dir = a.dir.Right().Right()
+		case chance >= 80:
+			a.dir = a.dir.Left().Left()
+		case chance >= 65:
+			a.dir = a.dir.Right()
+		case chance >= 50:
+			a.dir = a.dir.Left()
+		}
+	}
+	a.ILoc, _ = s.gr.Add(a.ILoc, a.dir.IVec())
+	a.apply()
+	c := s.gr.At(a.ILoc)
+	c.P = a.P
+	c.Alpha = 1
+
+	// leave a trail!
+	s.trail(a, a.dir.Right().Right())
+	s.trail(a, a.dir.Left().Left())
+
+	for j := range s.gr.Cells[s.fadeColumn] {
+		s.gr.Cells[s.fadeColumn][j].IncAlpha(-0.003)
+	}
+	s.fadeColumn = (s.fadeColumn + 1) % s.gr.Width
+
+	n := 0
+	for _, sp := range s.splashes {
+		sp.cooldown--
+		if sp.cooldown >= 1 {
+			s.splashes[n] = sp
+			n++
+			continue
+		}
+		s.makeSound = true
+		s.sound = int(sp.P) / s.mode.colorMultiplier
+		s.octave = 0
+		s.gr.Splash(sp.ILoc, 1, 1, func(gr g.Grid, l g.ILoc, n int, c *g.Cell) {
+			c.P = s.palette.Toward(c.P, sp.P, 1)
+			c.IncAlpha(0.1)
+		})
+	}
+	s.splashes = s.splashes[:n]
+
+	if idx%2 == 0 {
+		if s.soundToggle {
+			voice.PlayOctave(2-idx, 0, 75)
+		}
+		s.soundToggle = !s.soundToggle
+		if s.makeSound {
+			voice.PlayOctave(s.sound, s.octave, 75)
+			s.makeSound = false
+		}
+	}
+	return true, nil
+}
+
+func (s *wanderingScene) Draw(screen *ebiten.Image) error {
+	s.gctx.Render(screen, func(t *ebiten.Image, scale float32) {
+		s.gr.Draw(t, scale)
+	})
+	return nil
+}
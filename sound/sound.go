@@ -82,3 +82,17 @@ func (v *Voice) Play(tone, volume int) {
 	ap.SetVolume(float64(volume) / 100)
 	ap.Play()
 }
+
+// NotesPerOctave is the number of tones PlayOctave treats as an octave.
+// The tone sets are built around the six hues of the default palette.
+const NotesPerOctave = 6
+
+// PlayOctave plays the given note, wrapped to a single octave, in the
+// given octave.
+func (v *Voice) PlayOctave(note, octave, volume int) {
+	if v == nil {
+		return
+	}
+	note = ((note % NotesPerOctave) + NotesPerOctave) % NotesPerOctave
+	v.Play(octave*NotesPerOctave+note, volume)
+}